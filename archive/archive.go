@@ -0,0 +1,562 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"unchrome_launcher/constants"
+
+	"github.com/fatih/color"
+)
+
+// Options describes how an archive is extracted into the bin directory.
+type Options struct {
+	// StripComponents is the number of leading path elements that are
+	// removed from every archive entry, or AutoStripComponents to detect it
+	// from the entries.
+	StripComponents int
+
+	// Executable is the name the browser executable must end up with. It
+	// names the file a single binary is copied to.
+	Executable string
+
+	// Limits are enforced on what the archive extracts to.
+	Limits Limits
+
+	// Lenient skips entries that cannot be extracted with a warning, rather
+	// than failing the extraction.
+	Lenient bool
+}
+
+// Extractor is implemented by every supported archive format.
+type Extractor interface {
+	// Name returns the name of the format, such as "zip".
+	Name() string
+
+	// Detect reports if header, the first bytes of a file, start an archive
+	// of this format.
+	Detect(header []byte) bool
+
+	// Entries calls visit with the name of every entry in the archive src,
+	// in order, and stops with the first error visit returns.
+	Entries(src string, visit func(name string) error) error
+
+	// Extract unpacks the archive src into the directory dest.
+	Extract(src string, dest string, options Options) error
+}
+
+// AutoStripComponents detects the number of path elements to strip. The
+// single directory that all entries of an archive are in is stripped, while
+// an archive with more than one entry at its root is extracted as it is.
+const AutoStripComponents = -1
+
+// headerSize is how many bytes of a file are read to detect its format.
+const headerSize = 512
+
+var extractors []Extractor
+
+// Register makes an Extractor available to Detect. It is intended to be
+// called from the init function of the file implementing the Extractor.
+func Register(extractor Extractor) {
+	for _, registered := range extractors {
+		if registered.Name() == extractor.Name() {
+			panic(fmt.Sprintf("archive: Register called twice for %s", extractor.Name()))
+		}
+	}
+
+	extractors = append(extractors, extractor)
+}
+
+// Detect returns the Extractor for the file at path, chosen by its content
+// rather than its name.
+func Detect(path string) (Extractor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	header = header[:n]
+
+	for _, extractor := range extractors {
+		if extractor.Detect(header) {
+			return extractor, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported archive format of [%s]", path)
+}
+
+// Extract detects the format of the archive src and unpacks it into dest.
+func Extract(src string, dest string, options Options) error {
+	extractor, err := Detect(src)
+	if err != nil {
+		return err
+	}
+
+	if options.StripComponents == AutoStripComponents {
+		count, err := detectStripComponents(extractor, src, options.Limits)
+		if err != nil {
+			return err
+		}
+
+		options.StripComponents = count
+	}
+
+	return extractor.Extract(src, dest, options)
+}
+
+// errMixedRoots stops listing the entries of an archive once they turn out
+// to have more than one top-level entry.
+var errMixedRoots = errors.New("more than one top-level entry")
+
+// detectStripComponents lists the entries of the archive src to find the
+// number of path elements AutoStripComponents stands for. The entry count
+// limit applies to the listing as it does to the extraction.
+func detectStripComponents(extractor Extractor, src string, limits Limits) (int, error) {
+	limiter, err := newLimiter(src, Limits{MaxEntries: limits.MaxEntries})
+	if err != nil {
+		return 0, err
+	}
+
+	var root rootDetector
+	err = extractor.Entries(src, func(name string) error {
+		if err := limiter.addEntry(name, -1); err != nil {
+			return err
+		}
+
+		if !root.add(name) {
+			return errMixedRoots
+		}
+
+		return nil
+	})
+
+	var limitError *LimitError
+	switch {
+	case errors.Is(err, errMixedRoots):
+		return 0, nil
+	case errors.As(err, &limitError):
+		return 0, err
+	case err != nil:
+		return 0, fmt.Errorf("could not list the entries of [%s]: %w", src, err)
+	}
+
+	return root.components(), nil
+}
+
+// rootDetector follows the entries of an archive to find the single
+// top-level directory they are all in.
+type rootDetector struct {
+	root   string
+	seen   bool
+	nested bool
+}
+
+// add records the entry name. It reports false as soon as the entries have
+// more than one top-level entry.
+func (d *rootDetector) add(name string) bool {
+	cleaned := filepath.ToSlash(filepath.Clean(name))
+	if cleaned == "." {
+		return true
+	}
+
+	first, rest, found := strings.Cut(cleaned, "/")
+	if !d.seen {
+		d.root, d.seen = first, true
+	} else if first != d.root {
+		return false
+	}
+
+	d.nested = d.nested || (found && rest != "")
+	return true
+}
+
+// components returns 1 when all entries are in one top-level directory, and
+// 0 otherwise. A single file at the root is not a directory to strip.
+func (d *rootDetector) components() int {
+	if d.nested {
+		return 1
+	}
+
+	return 0
+}
+
+// errIllegalPath is returned for an entry that would be extracted outside
+// of the destination directory.
+var errIllegalPath = errors.New("illegal file path")
+
+// maxLinkTarget is the longest symbolic link target read from the content of
+// an entry.
+const maxLinkTarget = 4096
+
+// extraction extracts the entries of one archive, one at a time, and keeps
+// the errors of the entries that failed.
+type extraction struct {
+	dest        string
+	options     Options
+	limiter     *limiter
+	failures    []error
+	directories []directory
+}
+
+// directory is an extracted directory whose mode and modification time are
+// restored once everything inside it has been written.
+type directory struct {
+	path string
+	info fs.FileInfo
+}
+
+// newExtraction prepares the extraction of the archive src into dest.
+func newExtraction(src string, dest string, options Options) (*extraction, error) {
+	limiter, err := newLimiter(src, options.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extraction{dest: dest, options: options, limiter: limiter}, nil
+}
+
+// extract extracts the entry name described by info, whose content is read
+// with open. link is the target of a link entry for formats that keep it
+// apart from the content, such as tar, and is empty otherwise. An entry that
+// fails is recorded, and the extraction carries on with the next one. Only a
+// broken limit or an illegal path, which make the whole archive suspect, are
+// returned to stop it.
+func (x *extraction) extract(name string, info fs.FileInfo, link string, open func() (io.ReadCloser, error)) error {
+	err := x.extractEntry(name, info, link, open)
+	if err == nil {
+		return nil
+	}
+
+	var limitError *LimitError
+	if errors.As(err, &limitError) || errors.Is(err, errIllegalPath) {
+		return err
+	}
+
+	x.failures = append(x.failures, fmt.Errorf("could not extract [%s]: %w", name, err))
+	return nil
+}
+
+// extractEntry writes a single entry to disk. The entry's file handles are
+// closed before it returns.
+func (x *extraction) extractEntry(name string, info fs.FileInfo, link string, open func() (io.ReadCloser, error)) error {
+	outPath, err := entryPath(x.dest, name, x.options.StripComponents)
+	if err != nil {
+		return err
+	}
+
+	size := int64(-1)
+	if info.Mode().IsRegular() {
+		size = info.Size()
+	}
+
+	// Every entry counts, even one that is stripped away entirely.
+	if err := x.limiter.addEntry(name, size); err != nil || outPath == "" {
+		return err
+	}
+
+	if err := checkParents(x.dest, outPath); err != nil {
+		return err
+	}
+
+	switch {
+	case info.IsDir():
+		// The directory stays writable until its own mode is restored by
+		// finish, so that its content can still be extracted.
+		if err := os.MkdirAll(outPath, 0755); err != nil {
+			return err
+		}
+
+		x.directories = append(x.directories, directory{path: outPath, info: info})
+		return nil
+	case info.Mode()&fs.ModeSymlink != 0:
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			return err
+		}
+
+		return x.symlink(outPath, link, open)
+	case info.Mode().IsRegular() && link != "":
+		// A hard link of tar, to an entry extracted before it.
+		target, err := entryPath(x.dest, link, x.options.StripComponents)
+		if err != nil {
+			return err
+		} else if target == "" {
+			return fmt.Errorf("%w: link to %s", errIllegalPath, link)
+		}
+
+		if err := checkParents(x.dest, target); err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			return err
+		}
+
+		os.Remove(outPath)
+		return os.Link(target, outPath)
+	case info.Mode().IsRegular():
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			return err
+		}
+
+		// A link left by an earlier entry of the same name is replaced, rather
+		// than written through.
+		if info, err := os.Lstat(outPath); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			if err := os.Remove(outPath); err != nil {
+				return err
+			}
+		}
+
+		rc, err := open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		if err := writeFile(outPath, rc, filePerm(info), x.limiter, name); err != nil {
+			return err
+		}
+
+		return restoreTime(outPath, info)
+	default:
+		// Devices, pipes and other special entries are skipped.
+		return nil
+	}
+}
+
+// symlink creates the symbolic link at outPath. Its target is link or, when
+// that is empty, the content of the entry, as zip and 7z store it. A link
+// that points outside of the destination directory is refused.
+func (x *extraction) symlink(outPath string, link string, open func() (io.ReadCloser, error)) error {
+	if link == "" {
+		rc, err := open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		content, err := io.ReadAll(io.LimitReader(rc, maxLinkTarget))
+		if err != nil {
+			return err
+		}
+
+		link = string(content)
+	}
+
+	// The link is checked where it really is, with the links above it
+	// resolved, rather than by its path alone.
+	dest, err := filepath.EvalSymlinks(x.dest)
+	if err != nil {
+		return err
+	}
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(outPath))
+	if err != nil {
+		return err
+	}
+
+	target := filepath.FromSlash(link)
+	if !linkInside(dest, filepath.Join(parent, filepath.Base(outPath)), target) {
+		return fmt.Errorf("%w: %s links to %s", errIllegalPath, outPath, link)
+	}
+
+	// An entry may appear more than once, in which case the last one wins.
+	os.Remove(outPath)
+	return os.Symlink(target, outPath)
+}
+
+// finish restores the directories and reports the outcome of the extraction.
+// The errors of all failed entries are returned as one, unless the extraction
+// is lenient, in which case they are only shown.
+func (x *extraction) finish() error {
+	// Deepest directories first, as restoring their times changes nothing
+	// above them, while a read-only parent would keep them from changing.
+	for i := len(x.directories) - 1; i >= 0; i-- {
+		dir := x.directories[i]
+		if err := os.Chmod(dir.path, dirPerm(dir.info)); err != nil {
+			x.failures = append(x.failures, fmt.Errorf("could not restore [%s]: %w", dir.path, err))
+		} else if err := restoreTime(dir.path, dir.info); err != nil {
+			x.failures = append(x.failures, fmt.Errorf("could not restore [%s]: %w", dir.path, err))
+		}
+	}
+
+	if len(x.failures) > 0 {
+		err := errors.Join(x.failures...)
+		if !x.options.Lenient {
+			return fmt.Errorf("%d entries could not be extracted:\n%w", len(x.failures), err)
+		}
+
+		log.Printf("%s: %d entries could not be extracted and were skipped:\n%s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), len(x.failures), err.Error())
+	}
+
+	fmt.Println("Extraction complete.")
+
+	return nil
+}
+
+// checkParents returns an errIllegalPath error when a directory between dest
+// and path is a symbolic link. Following a link extracted from the archive
+// could lead anywhere, even when every link on its own stays inside dest.
+func checkParents(dest string, path string) error {
+	relative, err := filepath.Rel(dest, filepath.Dir(path))
+	if err != nil || relative == "." {
+		return err
+	}
+
+	current := dest
+	for _, part := range strings.Split(relative, string(os.PathSeparator)) {
+		current = filepath.Join(current, part)
+
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			// Nothing below a missing directory exists either.
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s is below the link %s", errIllegalPath, path, current)
+		}
+	}
+
+	return nil
+}
+
+// linkInside reports if a symbolic link at linkPath pointing to target
+// resolves to dest or a path below it.
+func linkInside(dest string, linkPath string, target string) bool {
+	if target == "" || filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return false
+	}
+
+	dest = filepath.Clean(dest)
+	resolved := filepath.Join(filepath.Dir(linkPath), target)
+
+	return resolved == dest || strings.HasPrefix(resolved, dest+string(os.PathSeparator))
+}
+
+// filePerm returns the permissions a file entry is written with. Archives
+// made on Windows often carry none, in which case the file is made readable.
+func filePerm(info fs.FileInfo) os.FileMode {
+	if perm := info.Mode().Perm(); perm != 0 {
+		return perm
+	}
+
+	return 0644
+}
+
+// dirPerm returns the permissions restored on a directory entry. The owner
+// always keeps full access, so the install can be replaced later.
+func dirPerm(info fs.FileInfo) os.FileMode {
+	return info.Mode().Perm() | 0700
+}
+
+// restoreTime sets the modification time of path to that of the entry, when
+// the archive records one.
+func restoreTime(path string, info fs.FileInfo) error {
+	modTime := info.ModTime()
+	if modTime.IsZero() {
+		return nil
+	}
+
+	return os.Chtimes(path, modTime, modTime)
+}
+
+// entryPath returns where the archive entry name is extracted to below
+// dest, after removing its first strip path elements. An empty string is
+// returned when nothing is left of name.
+func entryPath(dest string, name string, strip int) (string, error) {
+	// Remove the leading directories the files of the archive are in, so that
+	// they end up right in dest.
+	name = stripComponents(name, strip)
+	if name == "" || name == "." {
+		return "", nil
+	}
+
+	outPath := filepath.Join(dest, name)
+
+	// Prevent ZipSlip vulnerability.
+	if !strings.HasPrefix(filepath.Clean(outPath), filepath.Clean(dest)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%w: %s", errIllegalPath, outPath)
+	}
+
+	return outPath, nil
+}
+
+// stripComponents removes the first count path elements from path. An empty
+// string is returned when nothing is left.
+func stripComponents(path string, count int) string {
+	// Clean path and split into parts
+	cleaned := filepath.ToSlash(filepath.Clean(path))
+	parts := strings.Split(cleaned, "/")
+
+	if len(parts) <= count {
+		return ""
+	}
+
+	// Join everything except the stripped elements
+	return filepath.Join(parts[count:]...)
+}
+
+// writeFile writes the content of r, the archive entry name, to a new file
+// at path with the given permissions, within the limits of limiter. A file
+// that could not be written completely is removed.
+func writeFile(path string, r io.Reader, perm os.FileMode, limiter *limiter, name string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(limiter.writer(name, file), r); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return file.Close()
+}
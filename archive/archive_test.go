@@ -0,0 +1,497 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarEntry is one entry of a tar.gz archive written by writeTarGz.
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+	pax      map[string]string
+}
+
+// writeTarGz writes entries as a tar.gz archive to a new file below dir.
+func writeTarGz(t *testing.T, dir string, entries []tarEntry) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "archive.tar.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	compressed := gzip.NewWriter(file)
+	w := tar.NewWriter(compressed)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:       entry.name,
+			Typeflag:   entry.typeflag,
+			Linkname:   entry.linkname,
+			Mode:       0755,
+			Size:       int64(len(entry.content)),
+			PAXRecords: entry.pax,
+		}
+		if entry.typeflag == tar.TypeXGlobalHeader {
+			header = &tar.Header{Name: entry.name, Typeflag: entry.typeflag, PAXRecords: entry.pax}
+		}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressed.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestExtractRefusesChainedSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTarGz(t, dir, []tarEntry{
+		{name: "top/a/", typeflag: tar.TypeDir},
+		{name: "top/a/l", typeflag: tar.TypeSymlink, linkname: ".."},
+		{name: "top/a/l/m", typeflag: tar.TypeSymlink, linkname: ".."},
+		{name: "top/a/l/m/evil", typeflag: tar.TypeReg, content: "evil"},
+	})
+
+	dest := filepath.Join(dir, "dest")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Extract(src, dest, Options{StripComponents: 1})
+	if !errors.Is(err, errIllegalPath) {
+		t.Fatalf("Extract() error = %v, want %v", err, errIllegalPath)
+	}
+
+	for _, path := range []string{filepath.Join(dir, "evil"), filepath.Join(dest, "evil")} {
+		if _, err := os.Lstat(path); err == nil {
+			t.Errorf("Extract() wrote %s", path)
+		}
+	}
+}
+
+func TestExtractSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTarGz(t, dir, []tarEntry{
+		{name: "top/chrome", typeflag: tar.TypeReg, content: "chrome"},
+		{name: "top/lib/", typeflag: tar.TypeDir},
+		{name: "top/lib/chrome", typeflag: tar.TypeSymlink, linkname: "../chrome"},
+	})
+
+	dest := filepath.Join(dir, "dest")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Extract(src, dest, Options{StripComponents: AutoStripComponents}); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dest, "lib", "chrome"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "chrome" {
+		t.Errorf("lib/chrome = %q, want %q", content, "chrome")
+	}
+}
+
+func TestExtractRefusesEscapingSymlink(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTarGz(t, dir, []tarEntry{
+		{name: "top/chrome", typeflag: tar.TypeReg, content: "chrome"},
+		{name: "top/passwd", typeflag: tar.TypeSymlink, linkname: "../../etc/passwd"},
+	})
+
+	err := Extract(src, filepath.Join(dir, "dest"), Options{StripComponents: 1})
+	if !errors.Is(err, errIllegalPath) {
+		t.Fatalf("Extract() error = %v, want %v", err, errIllegalPath)
+	}
+}
+
+func TestEntryPath(t *testing.T) {
+	dest := filepath.Join("bin", "staging")
+
+	tests := []struct {
+		name    string
+		strip   int
+		want    string
+		illegal bool
+	}{
+		{name: "top/chrome", strip: 1, want: filepath.Join(dest, "chrome")},
+		{name: "top/locales/en-US.pak", strip: 1, want: filepath.Join(dest, "locales", "en-US.pak")},
+		{name: "chrome", strip: 0, want: filepath.Join(dest, "chrome")},
+		{name: "./top/chrome", strip: 1, want: filepath.Join(dest, "chrome")},
+		{name: "top/", strip: 1, want: ""},
+		{name: "top", strip: 2, want: ""},
+		{name: "top/../../evil", strip: 0, illegal: true},
+		{name: "../evil", strip: 0, illegal: true},
+	}
+
+	for _, test := range tests {
+		got, err := entryPath(dest, test.name, test.strip)
+		if test.illegal {
+			if !errors.Is(err, errIllegalPath) {
+				t.Errorf("entryPath(%q, %d) error = %v, want %v", test.name, test.strip, err, errIllegalPath)
+			}
+			continue
+		}
+
+		if err != nil || got != test.want {
+			t.Errorf("entryPath(%q, %d) = %q, %v, want %q", test.name, test.strip, got, err, test.want)
+		}
+	}
+}
+
+func TestLinkInside(t *testing.T) {
+	dest := filepath.Join(string(os.PathSeparator), "bin")
+
+	tests := []struct {
+		link   string
+		target string
+		want   bool
+	}{
+		{link: "chrome", target: "chrome.real", want: true},
+		{link: filepath.Join("lib", "chrome"), target: filepath.Join("..", "chrome"), want: true},
+		{link: filepath.Join("lib", "up"), target: "..", want: true},
+		{link: "up", target: "..", want: false},
+		{link: filepath.Join("lib", "passwd"), target: filepath.Join("..", "..", "etc", "passwd"), want: false},
+		{link: "passwd", target: filepath.Join(string(os.PathSeparator), "etc", "passwd"), want: false},
+		{link: "empty", target: "", want: false},
+	}
+
+	for _, test := range tests {
+		got := linkInside(dest, filepath.Join(dest, test.link), test.target)
+		if got != test.want {
+			t.Errorf("linkInside(%q -> %q) = %v, want %v", test.link, test.target, got, test.want)
+		}
+	}
+}
+
+func TestRootDetector(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  int
+	}{
+		{name: "single root", names: []string{"top/", "top/chrome", "top/locales/en-US.pak"}, want: 1},
+		{name: "single root without directory entry", names: []string{"top/chrome", "top/chrome.dll"}, want: 1},
+		{name: "dot prefixed", names: []string{"./", "./top/", "./top/chrome"}, want: 1},
+		{name: "files at the root", names: []string{"chrome", "chrome.dll"}, want: 0},
+		{name: "root file next to a directory", names: []string{"top/chrome", "README"}, want: 0},
+		{name: "single file", names: []string{"chrome"}, want: 0},
+		{name: "empty directory", names: []string{"top/"}, want: 0},
+		{name: "no entries", names: nil, want: 0},
+	}
+
+	for _, test := range tests {
+		var root rootDetector
+		mixed := false
+		for _, name := range test.names {
+			if !root.add(name) {
+				mixed = true
+				break
+			}
+		}
+
+		got := root.components()
+		if mixed {
+			got = 0
+		}
+
+		if got != test.want {
+			t.Errorf("%s: components() = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestExtractAutoStripComponents(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		want    string
+	}{
+		{
+			name: "single root",
+			entries: []tarEntry{
+				{name: "top/", typeflag: tar.TypeDir},
+				{name: "top/chrome", typeflag: tar.TypeReg, content: "chrome"},
+			},
+			want: "chrome",
+		},
+		{
+			name: "files at the root",
+			entries: []tarEntry{
+				{name: "chrome", typeflag: tar.TypeReg, content: "chrome"},
+				{name: "README", typeflag: tar.TypeReg, content: "readme"},
+			},
+			want: "chrome",
+		},
+		{
+			name: "root file next to a directory",
+			entries: []tarEntry{
+				{name: "top/chrome", typeflag: tar.TypeReg, content: "chrome"},
+				{name: "README", typeflag: tar.TypeReg, content: "readme"},
+			},
+			want: filepath.Join("top", "chrome"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := writeTarGz(t, dir, test.entries)
+			dest := filepath.Join(dir, "dest")
+
+			if err := Extract(src, dest, Options{StripComponents: AutoStripComponents}); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+
+			if _, err := os.Stat(filepath.Join(dest, test.want)); err != nil {
+				t.Errorf("Extract() did not extract %s: %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestExtractAutoStripComponentsEntryLimit(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTarGz(t, dir, []tarEntry{
+		{name: "top/a", typeflag: tar.TypeReg, content: "a"},
+		{name: "top/b", typeflag: tar.TypeReg, content: "b"},
+		{name: "top/c", typeflag: tar.TypeReg, content: "c"},
+	})
+	dest := filepath.Join(dir, "dest")
+
+	err := Extract(src, dest, Options{StripComponents: AutoStripComponents, Limits: Limits{MaxEntries: 2}})
+
+	var limitError *LimitError
+	if !errors.As(err, &limitError) || limitError.Limit != "entry count" {
+		t.Fatalf("Extract() error = %v, want the entry count limit", err)
+	}
+
+	// The limit is hit while listing, before anything is extracted.
+	if _, err := os.Stat(dest); err == nil {
+		t.Errorf("Extract() created %s", dest)
+	}
+}
+
+func TestExtractSkipsPaxGlobalHeader(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTarGz(t, dir, []tarEntry{
+		{name: "pax_global_header", typeflag: tar.TypeXGlobalHeader, pax: map[string]string{"comment": "0123456789abcdef"}},
+		{name: "top/", typeflag: tar.TypeDir},
+		{name: "top/chrome", typeflag: tar.TypeReg, content: "chrome"},
+	})
+	dest := filepath.Join(dir, "dest")
+
+	if err := Extract(src, dest, Options{StripComponents: AutoStripComponents}); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "chrome")); err != nil {
+		t.Errorf("Extract() did not strip the root directory: %v", err)
+	}
+
+	for _, name := range []string{"pax_global_header", filepath.Join("top", "pax_global_header")} {
+		if _, err := os.Lstat(filepath.Join(dest, name)); err == nil {
+			t.Errorf("Extract() extracted %s", name)
+		}
+	}
+}
+
+func TestDetectBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "chrome.AppImage", content: "\x7fELF\x02\x01\x01", want: "binary"},
+		{name: "installer.exe", content: "MZ\x90\x00\x03", want: ""},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), test.name)
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		extractor, err := Detect(path)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("Detect(%s) = %s, want an error", test.name, extractor.Name())
+			}
+			continue
+		}
+
+		if err != nil || extractor.Name() != test.want {
+			t.Errorf("Detect(%s) = %v, %v, want %s", test.name, extractor, err, test.want)
+		}
+	}
+}
+
+// writeCorruptedZip writes a zip archive with the stored files names to a new
+// file below dir, each holding its own name as content. The content of the
+// files in corrupted is then overwritten, so that reading them fails their
+// checksum.
+func writeCorruptedZip(t *testing.T, dir string, names []string, corrupted ...string) string {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	w := zip.NewWriter(&buffer)
+	for _, name := range names {
+		f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte("content of " + name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buffer.Bytes()
+	for _, name := range corrupted {
+		index := bytes.Index(data, []byte("content of "+name))
+		if index < 0 {
+			t.Fatalf("content of %s not found", name)
+		}
+		copy(data[index:], "CONTENT")
+	}
+
+	path := filepath.Join(dir, "archive.zip")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// brokenTarEntries has two entries that cannot be extracted, a hard link to
+// a file the archive does not have and a file below a regular file, between
+// entries that can.
+var brokenTarEntries = []tarEntry{
+	{name: "chrome", typeflag: tar.TypeReg, content: "chrome"},
+	{name: "libEGL.so", typeflag: tar.TypeLink, linkname: "missing.so"},
+	{name: "chrome/locales", typeflag: tar.TypeReg, content: "locales"},
+	{name: "resources.pak", typeflag: tar.TypeReg, content: "resources"},
+}
+
+func TestExtractCollectsFailures(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTarGz(t, dir, brokenTarEntries)
+	dest := filepath.Join(dir, "dest")
+
+	err := Extract(src, dest, Options{})
+	if err == nil {
+		t.Fatal("Extract() error = nil, want the failed entries")
+	}
+
+	for _, want := range []string{"2 entries", "[libEGL.so]", "[chrome/locales]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Extract() error = %q, want it to mention %s", err, want)
+		}
+	}
+
+	// The entries after a failed one are still extracted.
+	if _, err := os.Stat(filepath.Join(dest, "resources.pak")); err != nil {
+		t.Errorf("Extract() stopped at the first failure: %v", err)
+	}
+}
+
+func TestExtractLenient(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTarGz(t, dir, brokenTarEntries)
+	dest := filepath.Join(dir, "dest")
+
+	if err := Extract(src, dest, Options{Lenient: true}); err != nil {
+		t.Fatalf("Extract() error = %v, want the failed entries skipped", err)
+	}
+
+	for _, name := range []string{"chrome", "resources.pak"} {
+		if _, err := os.Stat(filepath.Join(dest, name)); err != nil {
+			t.Errorf("Extract() did not extract %s: %v", name, err)
+		}
+	}
+
+	if _, err := os.Lstat(filepath.Join(dest, "libEGL.so")); err == nil {
+		t.Error("Extract() extracted the broken hard link")
+	}
+}
+
+func TestExtractZipCorruptedEntry(t *testing.T) {
+	names := []string{"chrome.exe", "chrome.dll", "resources.pak"}
+
+	for _, lenient := range []bool{false, true} {
+		dir := t.TempDir()
+		src := writeCorruptedZip(t, dir, names, "chrome.dll")
+		dest := filepath.Join(dir, "dest")
+
+		err := Extract(src, dest, Options{Lenient: lenient})
+		if lenient && err != nil {
+			t.Errorf("Extract(lenient) error = %v, want the corrupted entry skipped", err)
+		} else if !lenient && (err == nil || !strings.Contains(err.Error(), "[chrome.dll]")) {
+			t.Errorf("Extract() error = %v, want it to mention chrome.dll", err)
+		}
+
+		for _, name := range []string{"chrome.exe", "resources.pak"} {
+			content, err := os.ReadFile(filepath.Join(dest, name))
+			if err != nil || string(content) != "content of "+name {
+				t.Errorf("Extract(lenient = %v) did not extract %s: %q, %v", lenient, name, content, err)
+			}
+		}
+	}
+}
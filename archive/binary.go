@@ -0,0 +1,98 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// binaryExtractor installs a file that is not an archive at all, such as an
+// AppImage, by copying it into place as the browser executable.
+type binaryExtractor struct{}
+
+func init() {
+	Register(binaryExtractor{})
+}
+
+func (binaryExtractor) Name() string {
+	return "binary"
+}
+
+// Detect recognizes ELF executables, which AppImages are. Windows PE
+// executables are not accepted, as the ones published next to the portable
+// builds are installers and self-extracting archives rather than the
+// browser.
+func (binaryExtractor) Detect(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("\x7fELF"))
+}
+
+// Entries visits nothing, as a single binary has no entries to strip.
+func (binaryExtractor) Entries(src string, visit func(name string) error) error {
+	return nil
+}
+
+func (binaryExtractor) Extract(src string, dest string, options Options) error {
+	if options.Executable == "" {
+		return fmt.Errorf("no executable name to install [%s] as", src)
+	}
+
+	outPath, err := entryPath(dest, options.Executable, 0)
+	if err != nil {
+		return err
+	}
+
+	limiter, err := newLimiter(src, options.Limits)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := writeFile(outPath, in, 0755, limiter, filepath.Base(src)); err != nil {
+		return fmt.Errorf("could not install [%s] as [%s]: %w", src, outPath, err)
+	}
+
+	fmt.Println("Extraction complete.")
+
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Limits protect against archives that would fill the disk or create
+// millions of files, whether on purpose or because they are corrupted. A
+// limit of zero is not enforced.
+type Limits struct {
+	// MaxSize is the most bytes all entries together may extract to.
+	MaxSize int64
+
+	// MaxEntries is the most entries the archive may have.
+	MaxEntries int
+
+	// MaxFileSize is the most bytes a single entry may extract to.
+	MaxFileSize int64
+
+	// MaxRatio is the most the extracted bytes may be a multiple of the size
+	// of the archive.
+	MaxRatio int64
+}
+
+// LimitError is returned when extracting an archive would exceed one of its
+// Limits. Extraction stops at the entry that exceeded it.
+type LimitError struct {
+	// Limit names the limit that was exceeded.
+	Limit string
+
+	// Max is the value of the limit, in a readable form.
+	Max string
+
+	// Entry is the name of the entry being extracted when the limit was hit.
+	Entry string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("archive exceeds the %s limit of %s at entry[%s]", e.Limit, e.Max, e.Entry)
+}
+
+// limiter keeps track of what an extraction has produced so far and
+// enforces the Limits on it. Declared sizes are checked up front, but only
+// the bytes actually written are trusted.
+type limiter struct {
+	limits      Limits
+	archiveSize int64
+	entries     int
+	written     int64
+}
+
+// newLimiter returns a limiter for extracting the archive src.
+func newLimiter(src string, limits Limits) (*limiter, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &limiter{limits: limits, archiveSize: info.Size()}, nil
+}
+
+// addEntry counts the entry name, whose declared size is size, or -1 when
+// unknown.
+func (l *limiter) addEntry(name string, size int64) error {
+	l.entries++
+	if l.limits.MaxEntries > 0 && l.entries > l.limits.MaxEntries {
+		return &LimitError{Limit: "entry count", Max: fmt.Sprint(l.limits.MaxEntries), Entry: name}
+	}
+
+	if size < 0 {
+		return nil
+	}
+
+	if l.limits.MaxFileSize > 0 && size > l.limits.MaxFileSize {
+		return &LimitError{Limit: "file size", Max: formatSize(l.limits.MaxFileSize), Entry: name}
+	}
+
+	return l.checkTotal(name, l.written+size)
+}
+
+// checkTotal checks that total extracted bytes stay within the total size
+// and compression ratio limits.
+func (l *limiter) checkTotal(name string, total int64) error {
+	if l.limits.MaxSize > 0 && total > l.limits.MaxSize {
+		return &LimitError{Limit: "total size", Max: formatSize(l.limits.MaxSize), Entry: name}
+	}
+
+	if l.limits.MaxRatio > 0 && l.archiveSize > 0 && total > l.limits.MaxRatio*l.archiveSize {
+		return &LimitError{Limit: "compression ratio", Max: fmt.Sprintf("%d:1", l.limits.MaxRatio), Entry: name}
+	}
+
+	return nil
+}
+
+// writer wraps w, the file the entry name is extracted to, so that writing
+// it fails with a LimitError before any limit is exceeded.
+func (l *limiter) writer(name string, w io.Writer) io.Writer {
+	return &limitedWriter{limiter: l, name: name, w: w}
+}
+
+// limitedWriter counts the bytes written to an extracted file.
+type limitedWriter struct {
+	limiter *limiter
+	name    string
+	w       io.Writer
+	written int64
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	size := int64(len(p))
+
+	if w.limiter.limits.MaxFileSize > 0 && w.written+size > w.limiter.limits.MaxFileSize {
+		return 0, &LimitError{Limit: "file size", Max: formatSize(w.limiter.limits.MaxFileSize), Entry: w.name}
+	}
+
+	if err := w.limiter.checkTotal(w.name, w.limiter.written+size); err != nil {
+		return 0, err
+	}
+
+	n, err := w.w.Write(p)
+	w.written += int64(n)
+	w.limiter.written += int64(n)
+	return n, err
+}
+
+// formatSize formats a number of bytes in the largest whole unit.
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<30 && size%(1<<30) == 0:
+		return fmt.Sprintf("%d GB", size>>30)
+	case size >= 1<<20 && size%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", size>>20)
+	default:
+		return fmt.Sprintf("%d bytes", size)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractLimits(t *testing.T) {
+	entries := []tarEntry{
+		{name: "top/", typeflag: tar.TypeDir},
+		{name: "top/chrome", typeflag: tar.TypeReg, content: strings.Repeat("a", 4096)},
+		{name: "top/resources.pak", typeflag: tar.TypeReg, content: strings.Repeat("b", 4096)},
+	}
+
+	tests := []struct {
+		name   string
+		limits Limits
+		limit  string
+	}{
+		{name: "within limits", limits: Limits{MaxSize: 8192, MaxEntries: 3, MaxFileSize: 4096, MaxRatio: 1000}},
+		{name: "no limits", limits: Limits{}},
+		{name: "total size", limits: Limits{MaxSize: 8191}, limit: "total size"},
+		{name: "entry count", limits: Limits{MaxEntries: 2}, limit: "entry count"},
+		{name: "file size", limits: Limits{MaxFileSize: 4095}, limit: "file size"},
+		{name: "compression ratio", limits: Limits{MaxRatio: 1}, limit: "compression ratio"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := writeTarGz(t, dir, entries)
+
+			err := Extract(src, filepath.Join(dir, "dest"), Options{StripComponents: 1, Limits: test.limits})
+			if test.limit == "" {
+				if err != nil {
+					t.Fatalf("Extract() error = %v", err)
+				}
+				return
+			}
+
+			var limitError *LimitError
+			if !errors.As(err, &limitError) {
+				t.Fatalf("Extract() error = %v, want a LimitError", err)
+			}
+
+			if limitError.Limit != test.limit {
+				t.Errorf("Extract() exceeded the %s limit, want %s", limitError.Limit, test.limit)
+			}
+		})
+	}
+}
+
+// TestLimitedWriterIgnoresDeclaredSize makes sure the bytes written are
+// limited, not only the size an entry declares.
+func TestLimitedWriterIgnoresDeclaredSize(t *testing.T) {
+	l := &limiter{limits: Limits{MaxFileSize: 100, MaxSize: 150}}
+
+	if err := l.addEntry("small", 10); err != nil {
+		t.Fatalf("addEntry() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	_, err := l.writer("small", &out).Write(make([]byte, 101))
+
+	var limitError *LimitError
+	if !errors.As(err, &limitError) || limitError.Limit != "file size" {
+		t.Fatalf("Write() error = %v, want the file size limit", err)
+	}
+
+	if out.Len() != 0 {
+		t.Errorf("Write() wrote %d bytes past the limit", out.Len())
+	}
+
+	if _, err := l.writer("second", &out).Write(make([]byte, 100)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := l.writer("third", &out).Write(make([]byte, 51)); !errors.As(err, &limitError) || limitError.Limit != "total size" {
+		t.Fatalf("Write() error = %v, want the total size limit", err)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 8 << 30, want: "8 GB"},
+		{size: 2048 << 20, want: "2 GB"},
+		{size: 100 << 20, want: "100 MB"},
+		{size: 1500, want: "1500 bytes"},
+	}
+
+	for _, test := range tests {
+		if got := formatSize(test.size); got != test.want {
+			t.Errorf("formatSize(%d) = %q, want %q", test.size, got, test.want)
+		}
+	}
+}
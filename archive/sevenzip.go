@@ -0,0 +1,98 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package archive
+
+import (
+	"bytes"
+
+	"github.com/bodgit/sevenzip"
+	"github.com/schollz/progressbar/v3"
+)
+
+// sevenZipExtractor extracts 7z archives, as published by winchrome.
+type sevenZipExtractor struct{}
+
+func init() {
+	Register(sevenZipExtractor{})
+}
+
+func (sevenZipExtractor) Name() string {
+	return "7z"
+}
+
+func (sevenZipExtractor) Detect(header []byte) bool {
+	return bytes.HasPrefix(header, []byte("7z\xbc\xaf\x27\x1c"))
+}
+
+func (sevenZipExtractor) Entries(src string, visit func(name string) error) error {
+	r, err := sevenzip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if err := visit(f.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sevenZipExtractor) Extract(src string, dest string, options Options) error {
+	x, err := newExtraction(src, dest, options)
+	if err != nil {
+		return err
+	}
+
+	r, err := sevenzip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	bar := progressbar.NewOptions(
+		len(r.File),
+		progressbar.OptionShowCount(),
+		progressbar.OptionSetDescription("unzipping"))
+
+	for _, f := range r.File {
+		bar.Add(1)
+
+		if err := x.extract(f.Name, f.FileInfo(), "", f.Open); err != nil {
+			return err
+		}
+	}
+
+	return x.finish()
+}
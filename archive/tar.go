@@ -0,0 +1,185 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/ulikunitz/xz"
+)
+
+// tarExtractor extracts compressed tar archives, as published for Linux.
+type tarExtractor struct {
+	name       string
+	magic      []byte
+	decompress func(r io.Reader) (io.Reader, error)
+}
+
+func init() {
+	Register(tarExtractor{
+		name:  "tar.gz",
+		magic: []byte("\x1f\x8b"),
+		decompress: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+	})
+
+	Register(tarExtractor{
+		name:  "tar.xz",
+		magic: []byte("\xfd7zXZ\x00"),
+		decompress: func(r io.Reader) (io.Reader, error) {
+			return xz.NewReader(r)
+		},
+	})
+}
+
+// isTarMetadata reports if header holds metadata about other entries, such
+// as the pax_global_header git archive writes, rather than an entry of its
+// own.
+func isTarMetadata(header *tar.Header) bool {
+	switch header.Typeflag {
+	case tar.TypeXGlobalHeader, tar.TypeXHeader, tar.TypeGNULongName, tar.TypeGNULongLink:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t tarExtractor) Name() string {
+	return t.name
+}
+
+func (t tarExtractor) Detect(header []byte) bool {
+	return bytes.HasPrefix(header, t.magic)
+}
+
+// Entries reads through the archive, since a tar archive has no index to
+// list its entries from.
+func (t tarExtractor) Entries(src string, visit func(name string) error) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	bar := progressbar.DefaultBytes(info.Size(), "reading")
+	defer bar.Finish()
+
+	decompressed, err := t.decompress(io.TeeReader(file, bar))
+	if err != nil {
+		return fmt.Errorf("could not read [%s] as %s: %w", src, t.name, err)
+	}
+
+	r := tar.NewReader(decompressed)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if isTarMetadata(header) {
+			continue
+		}
+
+		if err := visit(header.Name); err != nil {
+			return err
+		}
+	}
+}
+
+func (t tarExtractor) Extract(src string, dest string, options Options) error {
+	x, err := newExtraction(src, dest, options)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// A tar archive has no index to count its entries from, so the progress
+	// is measured in compressed bytes read.
+	bar := progressbar.DefaultBytes(info.Size(), "extracting")
+
+	decompressed, err := t.decompress(io.TeeReader(file, bar))
+	if err != nil {
+		return fmt.Errorf("could not read [%s] as %s: %w", src, t.name, err)
+	}
+
+	// The entries of a tar archive can only be read in order, so a corrupted
+	// stream stops the extraction.
+	r := tar.NewReader(decompressed)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("could not read [%s]: %w", src, err)
+		}
+
+		if isTarMetadata(header) {
+			continue
+		}
+
+		open := func() (io.ReadCloser, error) {
+			return io.NopCloser(r), nil
+		}
+
+		if err := x.extract(header.Name, header.FileInfo(), header.Linkname, open); err != nil {
+			return err
+		}
+	}
+
+	bar.Finish()
+
+	return x.finish()
+}
@@ -0,0 +1,278 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/distribution"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// stageUpdateCmd is started in the background by updateandrun to download
+// and stage an update while the browser is already running.
+var stageUpdateCmd = &cobra.Command{
+	Use:    "stageupdate",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		stageUpdateCommand(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stageUpdateCmd)
+}
+
+func stageUpdateCommand(_ *cobra.Command, _ []string) {
+	// Another launcher is already updating, so there is nothing left to do.
+	lock, err := lockUpdates()
+	if errors.Is(err, errLockHeld) {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Not staging an update: %s", err.Error())
+		}
+		return
+	} else if err != nil {
+		log.Fatalf("%s: Background update failed: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+	defer lock.Release()
+
+	if err := stageUpdate(); err != nil {
+		log.Fatalf("%s: Background update failed: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	recordLastCheck()
+}
+
+// pendingDirectory returns the sibling directory of binPath that holds an
+// update staged in the background until it is applied.
+func pendingDirectory(binPath string) string {
+	return filepath.Clean(binPath) + ".pending"
+}
+
+// startBackgroundUpdate starts a detached copy of the launcher that runs the
+// stageupdate command, so that the browser does not have to wait for it.
+func startBackgroundUpdate() error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := []string{stageUpdateCmd.Name()}
+	if cfgFile != constants.EMPTY {
+		args = append(args, "--config", cfgFile)
+	}
+
+	// Flags given to this launcher decide what the update installs, so the
+	// background update has to see them too.
+	for _, name := range []string{"allow-downgrade", constants.LENIENT} {
+		if flag := rootCmd.PersistentFlags().Lookup(name); flag.Changed {
+			args = append(args, fmt.Sprintf("--%s=%s", name, flag.Value.String()))
+		}
+	}
+
+	command := exec.Command(exePath, args...)
+	command.Dir = globals.ExeDir
+	command.SysProcAttr = detachedProcessAttributes()
+
+	if err := command.Start(); err != nil {
+		return err
+	}
+
+	if viper.GetBool(constants.DEBUG) {
+		log.Printf("Started background update (PID: %d)\n", command.Process.Pid)
+	}
+
+	// The background update outlives us, so there is nothing to wait for.
+	return command.Process.Release()
+}
+
+// stageUpdate downloads the release that should replace the installed one
+// and extracts it into the pending directory, where it waits until
+// applyPendingUpdate switches to it on the next launch.
+func stageUpdate() error {
+	provider := currentProvider()
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+	pendingPath := pendingDirectory(binPath)
+
+	// A pending directory that was never recorded is what is left of a
+	// background update that was interrupted while moving it into place.
+	pendingRelease := loadState().PendingRelease
+	if pendingRelease == constants.EMPTY {
+		os.RemoveAll(pendingPath)
+	}
+
+	release, err := findUpdate(provider)
+	if err != nil || release == nil {
+		return err
+	}
+
+	if release.TagName == pendingRelease {
+		if _, err := os.Stat(pendingPath); err == nil {
+			log.Printf("Release[%s] is already staged and will be used on the next launch.", release.TagName)
+			return nil
+		}
+	}
+
+	log.Printf("Staging %s release[%s] in the background...\n", provider.Name(), release.TagName)
+
+	// Downloads of the .part file are resumed if a previous background
+	// update was interrupted.
+	archivePath, err := downloadRelease(provider, release)
+	if err != nil {
+		return err
+	}
+
+	if _, err := stagePendingUpdate(archivePath, binPath, provider, release.TagName, releaseChannel()); err != nil {
+		return err
+	}
+
+	log.Printf("Release[%s] is staged and will be used on the next launch.\n", release.TagName)
+
+	return nil
+}
+
+// stagePendingUpdate extracts archivePath and moves it into the pending
+// directory next to binPath, recording it as tag from channel so that
+// applyPendingUpdate switches to it on the next launch. The pending
+// directory is returned.
+func stagePendingUpdate(archivePath string, binPath string, provider distribution.Provider, tag string, channel string) (string, error) {
+	pendingPath := pendingDirectory(binPath)
+
+	stagingPath, err := stageArchive(archivePath, binPath, provider, tag)
+	if err != nil {
+		return "", err
+	}
+
+	// Forget the previous pending update before it is replaced, so that an
+	// interruption can never leave a record pointing at the wrong tree.
+	forgetPendingUpdate()
+
+	if err := os.RemoveAll(pendingPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return "", fmt.Errorf("could not remove pending directory[%s]: %w", pendingPath, err)
+	}
+
+	if err := os.Rename(stagingPath, pendingPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return "", fmt.Errorf("could not move staged update to [%s]: %w", pendingPath, err)
+	}
+
+	saveState(func(state *launcherState) {
+		state.PendingRelease = tag
+		state.PendingChannel = channel
+	})
+
+	return pendingPath, nil
+}
+
+// applyPendingUpdate switches to an update staged in the background, or
+// deferred because the browser was running. The pending update is discarded
+// if it is incomplete, and kept for the next launch if the browser is still
+// using the current install.
+func applyPendingUpdate() {
+	state := loadState()
+	pendingRelease := state.PendingRelease
+	if pendingRelease == constants.EMPTY {
+		return
+	}
+
+	provider := currentProvider()
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+	pendingPath := pendingDirectory(binPath)
+
+	if err := validateInstall(pendingPath, provider); err != nil {
+		log.Printf("%s: Discarding pending update[%s]: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), pendingRelease, err.Error())
+		discardPendingUpdate(pendingPath)
+		return
+	}
+
+	installedVersion := state.InstalledRelease
+	if pendingRelease == installedVersion {
+		discardPendingUpdate(pendingPath)
+		return
+	}
+
+	// Keep the pending update until the browser has exited.
+	if err := browserRunning(binPath); err != nil {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Not applying pending update[%s] yet: %s", pendingRelease, err.Error())
+		}
+		return
+	}
+
+	// Should the browser start in the meantime, moving the bin directory
+	// fails on Windows, and the swap is retried on the next launch.
+	if err := swapInstall(pendingPath, binPath, installedVersion); err != nil {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Could not apply pending update[%s] yet: %s", pendingRelease, err.Error())
+		}
+		return
+	}
+
+	log.Printf("Updated %s from [%s] to [%s].\n", provider.Name(), installedVersion, pendingRelease)
+
+	recordInstall(binPath, pendingRelease, state.PendingChannel)
+	forgetPendingUpdate()
+}
+
+// discardPendingUpdate removes a pending update and its record.
+func discardPendingUpdate(pendingPath string) {
+	if err := os.RemoveAll(pendingPath); err != nil {
+		log.Printf("%s: Could not remove pending update[%s]: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), pendingPath, err.Error())
+	}
+
+	forgetPendingUpdate()
+}
+
+// forgetPendingUpdate clears the record of a pending update.
+func forgetPendingUpdate() {
+	saveState(func(state *launcherState) {
+		state.PendingRelease = constants.EMPTY
+		state.PendingChannel = constants.EMPTY
+	})
+}
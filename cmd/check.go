@@ -0,0 +1,99 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"unchrome_launcher/constants"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: constants.CHECK_SHORT_DESCRIPTION,
+	Long:  constants.CHECK_LONG_DESCRIPTION,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(checkForUpdate(cmd, args))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+// checkForUpdate reports the installed and latest versions and returns the
+// exit code describing the result.
+func checkForUpdate(_ *cobra.Command, _ []string) int {
+	provider := currentProvider()
+	state := loadState()
+	installedVersion := state.InstalledRelease
+
+	log.Println("     Distribution:", provider.Name())
+	log.Println("          Channel:", releaseChannel())
+	log.Println("Installed Version:", installedVersion)
+
+	release, err := resolveRelease(provider)
+	if err != nil {
+		log.Printf("%s: %s\n", color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		return constants.EXIT_CODE_RELEASE_LOOKUP_FAILED
+	}
+
+	log.Println("   Latest Version:", release.TagName)
+
+	asset, err := provider.SelectAsset(release)
+	if err != nil {
+		log.Printf("%s: %s\n", color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		return constants.EXIT_CODE_ASSET_NOT_FOUND
+	}
+
+	log.Println("            Asset:", asset.Name)
+
+	// The same decision as 'update', so that a held version or a release
+	// outside the version constraint is reported the way it would be
+	// handled.
+	update, downgrade := wantsRelease(provider, state, release)
+	if !update {
+		return constants.EXIT_CODE_UP_TO_DATE
+	}
+
+	if downgrade {
+		log.Println(color.YellowString("A downgrade to the latest version is available."))
+	} else {
+		log.Println(color.YellowString("An update is available."))
+	}
+	return constants.EXIT_CODE_UPDATE_AVAILABLE
+}
@@ -0,0 +1,344 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/httpclient"
+
+	"github.com/fatih/color"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/viper"
+)
+
+// downloadFile downloads url into path while showing a progress bar, and
+// returns the hex encoded SHA-256 digest of the downloaded content.
+//
+// The content is written to path + ".part" and only renamed to path once it
+// is complete. An existing part file from an interrupted download is resumed
+// with a Range request, guarded by If-Range so that a changed file on the
+// server starts the download over. When expectedSize is greater than zero,
+// the finished download must be exactly that many bytes.
+//
+// A download that is interrupted part way is resumed straight away, up to
+// the configured number of retries. After that the part file is kept for the
+// next update.
+func downloadFile(url string, path string, expectedSize int64) (string, error) {
+	for attempt := 0; ; attempt++ {
+		digest, err := downloadAttempt(url, path, expectedSize)
+		if !errors.Is(err, errDownloadInterrupted) {
+			return digest, err
+		}
+
+		if attempt >= httpclient.Retries {
+			return "", fmt.Errorf("%w, it will be resumed on the next update", err)
+		}
+
+		wait := httpclient.Backoff(attempt)
+		log.Printf("%s: %s, resuming in %s (%d of %d).\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), err.Error(), wait.Round(time.Second), attempt+1, httpclient.Retries)
+		time.Sleep(wait)
+	}
+}
+
+// errDownloadInterrupted marks a download that stopped part way because of
+// the connection, and can be resumed from its part file.
+var errDownloadInterrupted = errors.New("download interrupted")
+
+// downloadAttempt makes a single attempt at downloadFile.
+func downloadAttempt(url string, path string, expectedSize int64) (string, error) {
+	partPath := path + ".part"
+	validatorPath := partPath + ".validator"
+
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	// Without the validator of the original response, there is no safe way to
+	// know the part file still matches what the server has.
+	validator, err := os.ReadFile(validatorPath)
+	if err != nil || len(validator) == 0 {
+		offset = 0
+	}
+
+	if expectedSize > 0 && offset > expectedSize {
+		offset = 0
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", string(validator))
+	}
+
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer check(resp.Body.Close)
+
+	total := expectedSize
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return "", err
+		}
+
+		if start != offset {
+			return "", fmt.Errorf("server resumed at byte %d instead of %d", start, offset)
+		}
+
+		if total <= 0 {
+			total = size
+		}
+
+		log.Printf("Resuming download of [%s] at %d bytes.", filepath.Base(path), offset)
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset == expectedSize:
+		// The part file is already complete, only the rename was missed.
+		total = offset
+	case resp.StatusCode == http.StatusOK:
+		offset = 0
+		if total <= 0 {
+			total = resp.ContentLength
+		}
+	default:
+		return "", fmt.Errorf("download request failed: %s", resp.Status)
+	}
+
+	flags := os.O_RDWR | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return "", fmt.Errorf("could not create file: %w", err)
+	}
+	defer file.Close() // nolint:errcheck
+
+	// The digest covers the whole file, so feed it what was downloaded before.
+	hash := sha256.New()
+	if offset > 0 {
+		if _, err := io.Copy(hash, io.NewSectionReader(file, 0, offset)); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	if offset == 0 {
+		if err := os.WriteFile(validatorPath, []byte(responseValidator(resp)), 0644); err != nil {
+			return "", err
+		}
+	}
+
+	written := offset
+	if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		bar := progressbar.DefaultBytes(
+			total,
+			"downloading",
+		)
+		bar.Add64(offset)
+
+		// Never copy more than expected, which the progress bar would fail on.
+		var body io.Reader = resp.Body
+		if total > 0 {
+			body = io.LimitReader(resp.Body, total-offset)
+		}
+
+		n, err := io.Copy(io.MultiWriter(file, bar, hash), body)
+		written += n
+		if err != nil {
+			if httpclient.IsRetryable(err) {
+				return "", fmt.Errorf("%w after %d bytes: %w", errDownloadInterrupted, written, err)
+			}
+			return "", err
+		}
+
+		// Anything left in the body means the file is larger than expected.
+		if total > 0 && written == total {
+			if extra, _ := io.ReadFull(resp.Body, make([]byte, 1)); extra > 0 {
+				file.Close()
+				os.Remove(partPath)
+				os.Remove(validatorPath)
+				return "", fmt.Errorf("downloaded more than the expected %d bytes", total)
+			}
+		}
+	}
+
+	if total > 0 && written != total {
+		return "", fmt.Errorf("%w after %d of %d bytes", errDownloadInterrupted, written, total)
+	}
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+		return "", err
+	}
+	os.Remove(validatorPath)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// completeDownload returns the digest of the file at path when it is a
+// complete download of an earlier update, one with the expected digest or,
+// when none was published, the expected size. An empty string is returned
+// for anything else, including no file at all.
+func completeDownload(path string, expectedDigest string, expectedSize int64) string {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return constants.EMPTY
+	}
+
+	// Without a digest or a size, a complete file cannot be told apart from
+	// a truncated one.
+	if expectedDigest == constants.EMPTY && expectedSize <= 0 {
+		return constants.EMPTY
+	}
+
+	if expectedSize > 0 && info.Size() != expectedSize {
+		return constants.EMPTY
+	}
+
+	digest, err := fileDigest(path)
+	if err != nil {
+		return constants.EMPTY
+	}
+
+	if expectedDigest != constants.EMPTY && !strings.EqualFold(digest, expectedDigest) {
+		return constants.EMPTY
+	}
+
+	log.Printf("Using [%s], which was downloaded before.", filepath.Base(path))
+
+	return digest
+}
+
+// responseValidator returns the value to send as If-Range when resuming the
+// download of resp. Only a strong ETag or a Last-Modified date can be used.
+func responseValidator(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != constants.EMPTY && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+
+	return resp.Header.Get("Last-Modified")
+}
+
+// parseContentRange parses a "bytes start-end/size" Content-Range header and
+// returns the start offset and the complete size, which is -1 when unknown.
+func parseContentRange(contentRange string) (int64, int64, error) {
+	var start, end int64
+	var size string
+
+	if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%s", &start, &end, &size); err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range [%s]: %w", contentRange, err)
+	}
+
+	if size == "*" {
+		return start, -1, nil
+	}
+
+	total, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range [%s]: %w", contentRange, err)
+	}
+
+	return start, total, nil
+}
+
+// verifyDigest compares the digest computed while downloading path with the
+// expected one. On a mismatch the downloaded file is deleted so it can never
+// be extracted.
+func verifyDigest(path string, actual string, expected string) error {
+	if strings.EqualFold(actual, expected) {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("checksum mismatch for [%s]: expected[%s] actual[%s], and the file could not be deleted: %w",
+			path, expected, actual, err)
+	}
+
+	return fmt.Errorf("checksum mismatch for [%s]: expected[%s] actual[%s], the file has been deleted",
+		path, expected, actual)
+}
+
+// configureHTTPClient applies the HTTP_CONNECT_TIMEOUT, HTTP_READ_TIMEOUT and
+// HTTP_RETRIES settings to every request.
+func configureHTTPClient() {
+	httpclient.ConnectTimeout = httpTimeout(constants.HTTP_CONNECT_TIMEOUT)
+	httpclient.ReadTimeout = httpTimeout(constants.HTTP_READ_TIMEOUT)
+
+	retries := viper.GetInt(constants.HTTP_RETRIES)
+	if retries < 0 {
+		log.Fatalf("%s: Invalid %s[%d], use 0 to never retry.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), constants.HTTP_RETRIES, retries)
+		os.Exit(1)
+	}
+	httpclient.Retries = retries
+}
+
+// httpTimeout returns the parsed timeout setting key, which must be greater
+// than zero.
+func httpTimeout(key string) time.Duration {
+	timeout, err := time.ParseDuration(viper.GetString(key))
+	if err != nil || timeout <= 0 {
+		log.Fatalf("%s: Invalid %s[%s], use a duration such as '30s' or '1m'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), key, viper.GetString(key))
+		os.Exit(1)
+	}
+
+	return timeout
+}
@@ -0,0 +1,220 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"unchrome_launcher/httpclient"
+)
+
+// rangeServer serves content with the strong ETag etag, honouring Range and
+// If-Range, and records the Range header of every request.
+type rangeServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	ranges []string
+}
+
+func newRangeServer(t *testing.T, content []byte, etag string) *rangeServer {
+	t.Helper()
+
+	server := &rangeServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		server.ranges = append(server.ranges, r.Header.Get("Range"))
+		server.mu.Unlock()
+
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "archive.zip", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// requestedRanges returns the Range headers received so far.
+func (s *rangeServer) requestedRanges() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.ranges...)
+}
+
+// testContent returns size bytes of content to download.
+func testContent(size int) []byte {
+	return bytes.Repeat([]byte("0123456789"), size/10)
+}
+
+// writePartFile leaves part as an interrupted download of path, with the
+// validator of the response it came from.
+func writePartFile(t *testing.T, path string, part []byte, validator string) {
+	t.Helper()
+
+	if err := os.WriteFile(path+".part", part, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".part.validator", []byte(validator), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// withoutRetries makes a failed download fail straight away.
+func withoutRetries(t *testing.T) {
+	retries := httpclient.Retries
+	httpclient.Retries = 0
+	t.Cleanup(func() { httpclient.Retries = retries })
+}
+
+// checkDownloaded fails the test unless path holds content, digest is its
+// digest and the part file is gone.
+func checkDownloaded(t *testing.T, path string, digest string, content []byte) {
+	t.Helper()
+
+	sum := sha256.Sum256(content)
+	if want := hex.EncodeToString(sum[:]); digest != want {
+		t.Errorf("digest = %s, want %s", digest, want)
+	}
+
+	downloaded, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, content) {
+		t.Errorf("downloaded %d bytes that differ from the %d bytes served", len(downloaded), len(content))
+	}
+
+	for _, leftover := range []string{path + ".part", path + ".part.validator"} {
+		if _, err := os.Stat(leftover); err == nil {
+			t.Errorf("%s was not removed", leftover)
+		}
+	}
+}
+
+func TestDownloadFileResumesPartFile(t *testing.T) {
+	content := testContent(1000)
+	server := newRangeServer(t, content, `"v1"`)
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	writePartFile(t, path, content[:400], `"v1"`)
+
+	digest, err := downloadFile(server.URL, path, int64(len(content)))
+	if err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+
+	checkDownloaded(t, path, digest, content)
+
+	if ranges := server.requestedRanges(); len(ranges) != 1 || ranges[0] != "bytes=400-" {
+		t.Errorf("requested ranges = %q, want [bytes=400-]", ranges)
+	}
+}
+
+func TestDownloadFileRestartsChangedFile(t *testing.T) {
+	content := testContent(1000)
+	server := newRangeServer(t, content, `"v2"`)
+	path := filepath.Join(t.TempDir(), "archive.zip")
+
+	// The part file came from an older version of the file, so the If-Range
+	// mismatch makes the server answer with all of the new one.
+	writePartFile(t, path, bytes.Repeat([]byte("x"), 400), `"v1"`)
+
+	digest, err := downloadFile(server.URL, path, int64(len(content)))
+	if err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+
+	checkDownloaded(t, path, digest, content)
+}
+
+func TestDownloadFileCompletePartFile(t *testing.T) {
+	content := testContent(1000)
+	server := newRangeServer(t, content, `"v1"`)
+	path := filepath.Join(t.TempDir(), "archive.zip")
+
+	// Only the rename was missed, so the server answers the range with 416.
+	writePartFile(t, path, content, `"v1"`)
+
+	digest, err := downloadFile(server.URL, path, int64(len(content)))
+	if err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+
+	checkDownloaded(t, path, digest, content)
+}
+
+func TestDownloadFileSizeMismatch(t *testing.T) {
+	withoutRetries(t)
+
+	content := testContent(1000)
+	server := newRangeServer(t, content, `"v1"`)
+
+	tests := []struct {
+		name         string
+		expectedSize int64
+		want         string
+		keepPart     bool
+	}{
+		{name: "larger than expected", expectedSize: 500, want: "downloaded more than the expected 500 bytes"},
+		{name: "smaller than expected", expectedSize: 2000, want: "after 1000 of 2000 bytes", keepPart: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "archive.zip")
+
+			_, err := downloadFile(server.URL, path, test.expectedSize)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("downloadFile() error = %v, want %q", err, test.want)
+			}
+
+			if _, err := os.Stat(path); err == nil {
+				t.Errorf("%s was created from an incomplete download", path)
+			}
+
+			_, err = os.Stat(path + ".part")
+			if kept := err == nil; kept != test.keepPart {
+				t.Errorf("part file kept = %v, want %v", kept, test.keepPart)
+			}
+		})
+	}
+}
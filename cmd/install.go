@@ -0,0 +1,204 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// archiveVersionPattern finds a version in the name of an archive, such as
+// "ungoogled-chromium_139.0.7258.154-1.1_windows_x64.zip".
+var archiveVersionPattern = regexp.MustCompile(`[vV]?\d+\.\d+\.\d+\.\d+(?:-\d+(?:\.\d+)*)?`)
+
+var installTag string
+var installFrom string
+var installSHA256 string
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: constants.INSTALL_SHORT_DESCRIPTION,
+	Long:  constants.INSTALL_LONG_DESCRIPTION,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		install(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(installCmd)
+
+	installCmd.Flags().StringVar(&installTag, "tag", constants.EMPTY, "release tag to install, or the version to record for --from")
+	installCmd.Flags().StringVar(&installFrom, "from", constants.EMPTY, "path of a local archive to install")
+	installCmd.Flags().StringVar(&installSHA256, "sha256", constants.EMPTY, "expected SHA-256 checksum of the --from archive")
+}
+
+func install(_ *cobra.Command, _ []string) {
+	lock, err := lockUpdates()
+	if err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+	defer lock.Release()
+
+	if installFrom != constants.EMPTY {
+		err = installFromArchive(installFrom, installTag, installSHA256)
+	} else if installTag != constants.EMPTY {
+		err = installFromTag(installTag)
+	} else {
+		err = fmt.Errorf("either --tag or --from is required")
+	}
+
+	if err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+}
+
+// installFromTag downloads and installs the release with the given tag,
+// whether it is newer or older than the installed version.
+func installFromTag(tag string) error {
+	provider := currentProvider()
+
+	release, err := provider.ReleaseByTag(tag)
+	if err != nil {
+		return fmt.Errorf("could not get release[%s]: %w", tag, err)
+	}
+
+	installedVersion := loadState().InstalledRelease
+
+	log.Printf("Installing %s release[%s]...\n", provider.Name(), release.TagName)
+	log.Println("Installed Version:", installedVersion)
+
+	channel := constants.STABLE_CHANNEL
+	if release.Prerelease {
+		channel = constants.PRERELEASE_CHANNEL
+	}
+
+	return installRelease(provider, release, installedVersion, channel)
+}
+
+// installFromArchive installs an archive that is already on disk, without
+// any network access. The version to record is taken from tag, or from the
+// archive's file name when tag is empty.
+func installFromArchive(archivePath string, tag string, expectedDigest string) error {
+	provider := currentProvider()
+
+	archivePath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return err
+	}
+
+	if tag == constants.EMPTY {
+		tag = archiveVersionPattern.FindString(filepath.Base(archivePath))
+		if tag == constants.EMPTY {
+			return fmt.Errorf("could not determine the version of [%s] from its name, use --tag to give it", archivePath)
+		}
+	}
+
+	if expectedDigest == constants.EMPTY {
+		if viper.GetBool(constants.REQUIRE_CHECKSUM) {
+			return fmt.Errorf("no SHA-256 checksum given for [%s], use --sha256 to give it", archivePath)
+		}
+
+		log.Printf("%s: No SHA-256 checksum given for [%s], skipping verification.\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), archivePath)
+	} else {
+		digest, err := fileDigest(archivePath)
+		if err != nil {
+			return err
+		}
+
+		if !strings.EqualFold(digest, expectedDigest) {
+			return fmt.Errorf("checksum mismatch for [%s]: expected[%s] actual[%s]", archivePath, expectedDigest, digest)
+		}
+
+		log.Printf("Verified SHA-256 checksum [%s].", digest)
+	}
+
+	installedVersion := loadState().InstalledRelease
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+
+	log.Printf("Installing %s version[%s] from [%s]...\n", provider.Name(), tag, archivePath)
+	log.Println("Installed Version:", installedVersion)
+
+	deferred, err := installOrDefer(archivePath, binPath, provider, installedVersion, tag, constants.EMPTY)
+	if err != nil || deferred {
+		return err
+	}
+
+	recordInstall(binPath, tag, constants.EMPTY)
+	releaseHold()
+
+	// An update staged in the background would replace the version that was
+	// just installed on the next launch.
+	if loadState().PendingRelease != constants.EMPTY {
+		discardPendingUpdate(pendingDirectory(binPath))
+	}
+
+	log.Printf("Done.\n")
+
+	return nil
+}
+
+// fileDigest returns the hex encoded SHA-256 digest of the file at path.
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
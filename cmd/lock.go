@@ -0,0 +1,139 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/viper"
+)
+
+// errLockHeld is returned by lockFile when another process holds the lock.
+var errLockHeld = errors.New("lock is held by another process")
+
+// updateLock keeps other launcher processes from changing the install while
+// we download, extract or swap a release. The lock is held on an open file
+// in the install root, so the operating system releases it when the process
+// exits, however it exits.
+type updateLock struct {
+	file *os.File
+}
+
+// updateLockPath returns the path of the lock file in the install root.
+func updateLockPath() string {
+	return filepath.Join(globals.ExeDir, constants.UPDATE_LOCK_FILE_NAME)
+}
+
+// tryLockUpdates takes the update lock if it is free. If another process
+// holds it, errLockHeld is returned straight away.
+func tryLockUpdates() (*updateLock, error) {
+	path := updateLockPath()
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open lock file[%s]: %w", path, err)
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &updateLock{file: file}, nil
+}
+
+// lockUpdates takes the update lock, waiting up to UPDATE_LOCK_TIMEOUT for
+// another process to release it.
+func lockUpdates() (*updateLock, error) {
+	timeout := updateLockTimeout()
+	deadline := time.Now().Add(timeout)
+
+	lock, err := tryLockUpdates()
+	if !errors.Is(err, errLockHeld) || timeout <= 0 {
+		return lock, updateInProgressError(err)
+	}
+
+	log.Printf("Waiting up to %s for another update to finish...\n", timeout)
+
+	for time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+
+		lock, err = tryLockUpdates()
+		if !errors.Is(err, errLockHeld) {
+			return lock, err
+		}
+	}
+
+	return nil, updateInProgressError(err)
+}
+
+// updateInProgressError explains errLockHeld, and returns other errors as
+// they are.
+func updateInProgressError(err error) error {
+	if errors.Is(err, errLockHeld) {
+		return fmt.Errorf("another Unchrome Launcher is updating [%s]: %w", globals.ExeDir, err)
+	}
+
+	return err
+}
+
+// Release gives up the update lock. It is safe to call more than once.
+func (l *updateLock) Release() {
+	if l == nil || l.file == nil {
+		return
+	}
+
+	unlockFile(l.file)
+	l.file.Close()
+	l.file = nil
+}
+
+// updateLockTimeout returns the parsed UPDATE_LOCK_TIMEOUT setting.
+func updateLockTimeout() time.Duration {
+	timeout, err := time.ParseDuration(viper.GetString(constants.UPDATE_LOCK_TIMEOUT))
+	if err != nil {
+		log.Fatalf("%s: Invalid %s[%s], use a duration such as '30s' or '2m'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), constants.UPDATE_LOCK_TIMEOUT, viper.GetString(constants.UPDATE_LOCK_TIMEOUT))
+		os.Exit(1)
+	}
+
+	return timeout
+}
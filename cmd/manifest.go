@@ -0,0 +1,382 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"unchrome_launcher/constants"
+
+	"github.com/spf13/viper"
+)
+
+// installManifest describes an install: the release it was extracted from,
+// and every file the archive put there. It is kept inside the install, so
+// that every kept version carries its own.
+type installManifest struct {
+	Distribution  string         `json:"distribution"`
+	Tag           string         `json:"tag"`
+	Archive       string         `json:"archive"`
+	ArchiveSHA256 string         `json:"archive_sha256"`
+	Files         []manifestFile `json:"files"`
+}
+
+// manifestFile is a file extracted from the archive, with its path relative
+// to the install in slash form. Symbolic links record their target as Link
+// instead of a size and digest.
+type manifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+// manifestReport lists how an install differs from its manifest.
+type manifestReport struct {
+	Missing  []string
+	Modified []string
+	Extra    []string
+}
+
+// IsDamaged reports if files from the archive are missing or modified.
+// Extra files are the user's business.
+func (r *manifestReport) IsDamaged() bool {
+	return len(r.Missing) > 0 || len(r.Modified) > 0
+}
+
+// manifestPath returns the path of the manifest of the install in
+// installPath.
+func manifestPath(installPath string) string {
+	return filepath.Join(installPath, constants.MANIFEST_FILE_NAME)
+}
+
+// writeManifest records every file below installPath as extracted from the
+// archivePath of release tag of distribution.
+func writeManifest(installPath string, distribution string, tag string, archivePath string) error {
+	archiveDigest, err := fileDigest(archivePath)
+	if err != nil {
+		return fmt.Errorf("could not hash archive[%s]: %w", archivePath, err)
+	}
+
+	manifest := installManifest{
+		Distribution:  distribution,
+		Tag:           tag,
+		Archive:       archivePath,
+		ArchiveSHA256: archiveDigest,
+	}
+
+	err = walkInstall(installPath, func(path string, relativePath string, info fs.FileInfo) error {
+		if info.Mode()&fs.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			manifest.Files = append(manifest.Files, manifestFile{
+				Path: relativePath,
+				Link: link,
+			})
+			return nil
+		}
+
+		digest, err := fileDigest(path)
+		if err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, manifestFile{
+			Path:   relativePath,
+			Size:   info.Size(),
+			SHA256: digest,
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not list extracted files in [%s]: %w", installPath, err)
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(manifestPath(installPath), data, 0644); err != nil {
+		return fmt.Errorf("could not write manifest[%s]: %w", manifestPath(installPath), err)
+	}
+
+	return nil
+}
+
+// readManifest reads the manifest of the install in installPath. Installs
+// made before manifests were written have none, which is returned as an
+// error satisfying os.IsNotExist.
+func readManifest(installPath string) (*installManifest, error) {
+	data, err := os.ReadFile(manifestPath(installPath))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &installManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("could not parse manifest[%s]: %w", manifestPath(installPath), err)
+	}
+
+	return manifest, nil
+}
+
+// compareManifest checks the install in installPath against manifest. Files
+// are compared by size, and also by SHA-256 when hash is set. Symbolic links
+// are compared by their target.
+func compareManifest(installPath string, manifest *installManifest, hash bool) (*manifestReport, error) {
+	report := &manifestReport{}
+	tracked := make(map[string]bool, len(manifest.Files))
+
+	for _, file := range manifest.Files {
+		tracked[file.Path] = true
+		path := filepath.Join(installPath, filepath.FromSlash(file.Path))
+
+		if file.Link != constants.EMPTY {
+			link, err := os.Readlink(path)
+			if os.IsNotExist(err) {
+				report.Missing = append(report.Missing, file.Path)
+			} else if err != nil || link != file.Link {
+				report.Modified = append(report.Modified, file.Path)
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			report.Missing = append(report.Missing, file.Path)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if info.IsDir() || info.Size() != file.Size {
+			report.Modified = append(report.Modified, file.Path)
+			continue
+		}
+
+		if hash {
+			digest, err := fileDigest(path)
+			if err != nil {
+				return nil, err
+			}
+
+			if !strings.EqualFold(digest, file.SHA256) {
+				report.Modified = append(report.Modified, file.Path)
+			}
+		}
+	}
+
+	err := walkInstall(installPath, func(_ string, relativePath string, _ fs.FileInfo) error {
+		if !tracked[relativePath] {
+			report.Extra = append(report.Extra, relativePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// inspectInstall reads the manifest of the install in binPath and compares
+// the install against it, hashing every file when hash is set. A missing
+// manifest is returned as an error satisfying os.IsNotExist.
+func inspectInstall(binPath string, hash bool) (*installManifest, *manifestReport, error) {
+	manifest, err := readManifest(binPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := compareManifest(binPath, manifest, hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not verify [%s]: %w", binPath, err)
+	}
+
+	return manifest, report, nil
+}
+
+// walkInstall calls visit for every regular file and symbolic link below
+// installPath, except the manifest, with its path relative to installPath in
+// slash form. Symbolic links are not followed.
+func walkInstall(installPath string, visit func(path string, relativePath string, info fs.FileInfo) error) error {
+	return filepath.WalkDir(installPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !(entry.Type().IsRegular() || entry.Type()&fs.ModeSymlink != 0) {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(installPath, path)
+		if err != nil {
+			return err
+		}
+
+		relativePath = filepath.ToSlash(relativePath)
+		if relativePath == constants.MANIFEST_FILE_NAME {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		return visit(path, relativePath, info)
+	})
+}
+
+// carryOverUntrackedFiles copies the files a user added to the install in
+// oldPath, which are the ones its manifest does not list, into the install
+// in newPath. Files the new install already has are left as they are.
+// Without a manifest, nothing is carried over, and swapInstall keeps all of
+// oldPath instead.
+func carryOverUntrackedFiles(oldPath string, newPath string) error {
+	manifest, err := readManifest(oldPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	tracked := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		tracked[file.Path] = true
+	}
+
+	return walkInstall(oldPath, func(path string, relativePath string, info fs.FileInfo) error {
+		if tracked[relativePath] {
+			return nil
+		}
+
+		target := filepath.Join(newPath, filepath.FromSlash(relativePath))
+		if _, err := os.Lstat(target); err == nil {
+			return nil
+		}
+
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Keeping untracked file[%s].", relativePath)
+		}
+
+		var err error
+		if info.Mode()&fs.ModeSymlink != 0 {
+			err = copySymlink(path, target)
+		} else {
+			err = copyFile(path, target)
+		}
+		if err != nil {
+			return fmt.Errorf("could not keep untracked file[%s]: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// isUnmanagedInstall reports if the install in binPath has files but no
+// manifest, as installs made before the manifest existed do.
+func isUnmanagedInstall(binPath string) bool {
+	if _, err := readManifest(binPath); !os.IsNotExist(err) {
+		return false
+	}
+
+	hasFiles := false
+	walkInstall(binPath, func(string, string, fs.FileInfo) error {
+		hasFiles = true
+		return filepath.SkipAll
+	})
+
+	return hasFiles
+}
+
+// copyFile copies the regular file src to dst, creating the directories dst
+// needs and keeping the permissions and modification time of src.
+func copyFile(src string, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// copySymlink recreates the symbolic link src as dst, creating the
+// directories dst needs.
+func copySymlink(src string, dst string) error {
+	link, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	return os.Symlink(link, dst)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestManifestSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	installPath := filepath.Join(dir, "bin")
+	archivePath := filepath.Join(dir, "chrome.tar.gz")
+
+	if err := os.MkdirAll(installPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(installPath, "chrome"), []byte("chrome"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(archivePath, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("chrome", filepath.Join(installPath, "chromium")); err != nil {
+		t.Skipf("cannot create symbolic links: %v", err)
+	}
+
+	if err := writeManifest(installPath, "ungoogled", "139.0.7258.154-1.1", archivePath); err != nil {
+		t.Fatalf("writeManifest() error = %v", err)
+	}
+
+	manifest, err := readManifest(installPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []manifestFile{
+		{Path: "chrome", Size: 6, SHA256: manifest.Files[0].SHA256},
+		{Path: "chromium", Link: "chrome"},
+	}
+	if !slices.Equal(manifest.Files, want) {
+		t.Fatalf("writeManifest() recorded %+v, want %+v", manifest.Files, want)
+	}
+
+	// A link pointing elsewhere is modified, and a removed one missing.
+	link := filepath.Join(installPath, "chromium")
+	for _, test := range []struct {
+		change   func() error
+		missing  []string
+		modified []string
+	}{
+		{change: func() error { return nil }},
+		{
+			change: func() error {
+				os.Remove(link)
+				return os.Symlink("/usr/bin/chromium", link)
+			},
+			modified: []string{"chromium"},
+		},
+		{change: func() error { return os.Remove(link) }, missing: []string{"chromium"}},
+	} {
+		if err := test.change(); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err := compareManifest(installPath, manifest, true)
+		if err != nil {
+			t.Fatalf("compareManifest() error = %v", err)
+		}
+
+		if !slices.Equal(report.Missing, test.missing) || !slices.Equal(report.Modified, test.modified) {
+			t.Errorf("compareManifest() = missing %v, modified %v, want missing %v, modified %v",
+				report.Missing, report.Modified, test.missing, test.modified)
+		}
+	}
+}
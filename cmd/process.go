@@ -0,0 +1,197 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/viper"
+)
+
+// runningProcess is a process found by a processPlatform.
+type runningProcess struct {
+	PID  int
+	Path string
+}
+
+// processPlatform hides how running browsers are detected on each operating
+// system.
+type processPlatform interface {
+	// Processes returns the running processes whose executable path can be
+	// determined.
+	Processes() ([]runningProcess, error)
+
+	// ProfileInUse reports if a browser holds the lock of the profile in
+	// profilePath.
+	ProfileInUse(profilePath string) bool
+}
+
+// platform is the processPlatform of the operating system we run on.
+var platform processPlatform = newProcessPlatform()
+
+// browserRunningError is returned when the browser is running from the bin
+// directory that is about to be replaced.
+type browserRunningError struct {
+	binPath       string
+	processes     []runningProcess
+	profileLocked bool
+}
+
+func (e *browserRunningError) Error() string {
+	var pids []string
+	for _, process := range e.processes {
+		pids = append(pids, fmt.Sprint(process.PID))
+	}
+
+	message := fmt.Sprintf("the browser is running from [%s]", e.binPath)
+	if len(pids) > 0 {
+		message += fmt.Sprintf(" (PID %s)", strings.Join(pids, ", "))
+	} else if e.profileLocked {
+		message += " (the profile is locked)"
+	}
+
+	return message
+}
+
+// browserRunning returns a *browserRunningError if a process runs an
+// executable from below binPath, or if the profile is locked.
+func browserRunning(binPath string) error {
+	running := &browserRunningError{binPath: binPath}
+
+	processes, err := platform.Processes()
+	if err != nil {
+		return fmt.Errorf("could not list running processes: %w", err)
+	}
+
+	for _, process := range processes {
+		if isBelow(process.Path, binPath) {
+			running.processes = append(running.processes, process)
+		}
+	}
+
+	profilePath := filepath.Join(globals.ExeDir, viper.GetString(constants.PROFILE_DIRECTORY))
+	running.profileLocked = platform.ProfileInUse(profilePath)
+
+	if len(running.processes) > 0 || running.profileLocked {
+		return running
+	}
+
+	return nil
+}
+
+// waitForBrowserExit applies the RUNNING_BROWSER_ACTION setting before
+// binPath is replaced. With "wait" it polls until the browser has exited or
+// RUNNING_BROWSER_TIMEOUT has passed. A *browserRunningError is returned if
+// the browser is still running, which callers handle as "defer" where they
+// can and as "abort" otherwise.
+func waitForBrowserExit(binPath string) error {
+	err := browserRunning(binPath)
+	if err == nil || runningBrowserAction() != constants.RUNNING_BROWSER_WAIT {
+		return err
+	}
+
+	timeout := runningBrowserTimeout()
+
+	log.Printf("Waiting up to %s for the browser to exit...\n", timeout)
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(2 * time.Second)
+
+		err = browserRunning(binPath)
+		if err == nil {
+			return nil
+		}
+
+		var running *browserRunningError
+		if !errors.As(err, &running) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// isBrowserRunningError reports if err says the browser is still running.
+func isBrowserRunningError(err error) bool {
+	var running *browserRunningError
+	return errors.As(err, &running)
+}
+
+// runningBrowserAction returns the RUNNING_BROWSER_ACTION setting, which
+// must be RUNNING_BROWSER_ABORT, RUNNING_BROWSER_WAIT or RUNNING_BROWSER_DEFER.
+func runningBrowserAction() string {
+	action := strings.ToLower(viper.GetString(constants.RUNNING_BROWSER_ACTION))
+	if action != constants.RUNNING_BROWSER_ABORT && action != constants.RUNNING_BROWSER_WAIT && action != constants.RUNNING_BROWSER_DEFER {
+		log.Fatalf("%s: Unsupported %s[%s] found. Valid actions are '%s', '%s' and '%s'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), constants.RUNNING_BROWSER_ACTION, action,
+			constants.RUNNING_BROWSER_ABORT, constants.RUNNING_BROWSER_WAIT, constants.RUNNING_BROWSER_DEFER)
+		os.Exit(1)
+	}
+
+	return action
+}
+
+// runningBrowserTimeout returns the parsed RUNNING_BROWSER_TIMEOUT setting.
+func runningBrowserTimeout() time.Duration {
+	timeout, err := time.ParseDuration(viper.GetString(constants.RUNNING_BROWSER_TIMEOUT))
+	if err != nil {
+		log.Fatalf("%s: Invalid %s[%s], use a duration such as '5m' or '30s'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), constants.RUNNING_BROWSER_TIMEOUT, viper.GetString(constants.RUNNING_BROWSER_TIMEOUT))
+		os.Exit(1)
+	}
+
+	return timeout
+}
+
+// isBelow reports if path is inside directory.
+func isBelow(path string, directory string) bool {
+	path = filepath.Clean(path)
+	directory = filepath.Clean(directory) + string(filepath.Separator)
+
+	if runtime.GOOS == "windows" {
+		return strings.HasPrefix(strings.ToLower(path), strings.ToLower(directory))
+	}
+
+	return strings.HasPrefix(path, directory)
+}
@@ -0,0 +1,105 @@
+//go:build !windows
+
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// unixProcessPlatform finds processes through /proc, where it exists, and
+// checks the "SingletonLock" symlink Chromium keeps in the profile directory.
+type unixProcessPlatform struct{}
+
+func newProcessPlatform() processPlatform {
+	return unixProcessPlatform{}
+}
+
+func (unixProcessPlatform) Processes() ([]runningProcess, error) {
+	entries, err := os.ReadDir("/proc")
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var processes []runningProcess
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// Processes of other users cannot be read, and are skipped.
+		path, err := os.Readlink(filepath.Join("/proc", entry.Name(), "exe"))
+		if err != nil {
+			continue
+		}
+
+		processes = append(processes, runningProcess{PID: pid, Path: strings.TrimSuffix(path, " (deleted)")})
+	}
+
+	return processes, nil
+}
+
+// ProfileInUse reads the profile's SingletonLock, a symlink to
+// "<hostname>-<pid>", and checks that the process is still alive. A lock held
+// from another host is always treated as in use.
+func (unixProcessPlatform) ProfileInUse(profilePath string) bool {
+	target, err := os.Readlink(filepath.Join(profilePath, "SingletonLock"))
+	if err != nil {
+		return false
+	}
+
+	separator := strings.LastIndex(target, "-")
+	if separator < 0 {
+		return true
+	}
+
+	hostname, _ := os.Hostname()
+	if target[:separator] != hostname {
+		return true
+	}
+
+	pid, err := strconv.Atoi(target[separator+1:])
+	if err != nil {
+		return true
+	}
+
+	err = syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,108 @@
+//go:build windows
+
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procQueryFullProcessImageNameW = kernel32.NewProc("QueryFullProcessImageNameW")
+)
+
+const PROCESS_QUERY_LIMITED_INFORMATION = 0x1000 // Required to retrieve the image name of a process.
+
+// windowsProcessPlatform finds processes with a Toolhelp snapshot and checks
+// the "lockfile" Chromium keeps open in the profile directory.
+type windowsProcessPlatform struct{}
+
+func newProcessPlatform() processPlatform {
+	return windowsProcessPlatform{}
+}
+
+func (windowsProcessPlatform) Processes() ([]runningProcess, error) {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	var processes []runningProcess
+	for err = syscall.Process32First(snapshot, &entry); err == nil; err = syscall.Process32Next(snapshot, &entry) {
+		if path, ok := processImagePath(entry.ProcessID); ok {
+			processes = append(processes, runningProcess{PID: int(entry.ProcessID), Path: path})
+		}
+	}
+
+	return processes, nil
+}
+
+// processImagePath returns the full path of the executable of process pid.
+// Processes we are not allowed to query are skipped.
+func processImagePath(pid uint32) (string, bool) {
+	handle, err := syscall.OpenProcess(PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return "", false
+	}
+	defer syscall.CloseHandle(handle)
+
+	buffer := make([]uint16, syscall.MAX_LONG_PATH)
+	size := uint32(len(buffer))
+	result, _, _ := procQueryFullProcessImageNameW.Call(uintptr(handle), 0,
+		uintptr(unsafe.Pointer(&buffer[0])), uintptr(unsafe.Pointer(&size)))
+	if result == 0 {
+		return "", false
+	}
+
+	return syscall.UTF16ToString(buffer[:size]), true
+}
+
+// ProfileInUse tries to open the profile's lockfile. Chromium opens it
+// without sharing, so opening it fails for as long as the browser runs.
+func (windowsProcessPlatform) ProfileInUse(profilePath string) bool {
+	file, err := os.OpenFile(filepath.Join(profilePath, "lockfile"), os.O_RDWR, 0)
+	if err == nil {
+		file.Close()
+		return false
+	}
+
+	return !os.IsNotExist(err)
+}
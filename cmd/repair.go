@@ -0,0 +1,194 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/distribution"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// repairCmd represents the repair command
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: constants.REPAIR_SHORT_DESCRIPTION,
+	Long:  constants.REPAIR_LONG_DESCRIPTION,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		repair(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(repairCmd)
+}
+
+func repair(_ *cobra.Command, _ []string) {
+	provider := currentProvider()
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+
+	lock, err := lockUpdates()
+	if err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+	defer lock.Release()
+
+	manifest, report, err := inspectInstall(binPath, true)
+	if os.IsNotExist(err) {
+		log.Fatalf("%s: No manifest found in [%s]. Use 'install --tag' to reinstall the release.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), binPath)
+		os.Exit(1)
+	} else if err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	if !report.IsDamaged() {
+		log.Printf("Release[%s] is intact, nothing to repair.", manifest.Tag)
+		return
+	}
+
+	if err := repairInstall(provider, binPath, manifest, report); err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+}
+
+// repairDamagedInstall quickly checks the install in binPath against its
+// manifest by file size, and repairs it if files are missing or have
+// changed. Installs without a manifest are not checked.
+func repairDamagedInstall(provider distribution.Provider, binPath string) error {
+	manifest, report, err := inspectInstall(binPath, false)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if !report.IsDamaged() {
+		return nil
+	}
+
+	log.Printf("%s: Release[%s] in [%s] is damaged.\n",
+		color.YellowString(constants.WARNING_NORMAL_CASE), manifest.Tag, binPath)
+
+	return repairInstall(provider, binPath, manifest, report)
+}
+
+// repairInstall re-extracts the release of manifest into binPath. Files the
+// user added are carried over as on any update, and the damaged install is
+// removed rather than kept as a version.
+func repairInstall(provider distribution.Provider, binPath string, manifest *installManifest, report *manifestReport) error {
+	if !strings.EqualFold(manifest.Distribution, provider.Name()) {
+		return fmt.Errorf("install in [%s] is of distribution[%s], not the configured [%s], use 'install --tag' to replace it",
+			binPath, manifest.Distribution, provider.Name())
+	}
+
+	log.Printf("Repairing %s release[%s], %d missing and %d modified files...\n",
+		provider.Name(), manifest.Tag, len(report.Missing), len(report.Modified))
+
+	archivePath, err := repairArchive(provider, manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := waitForBrowserExit(binPath); err != nil {
+		return fmt.Errorf("could not repair, %w", err)
+	}
+
+	if err := installArchive(archivePath, binPath, provider, constants.EMPTY, manifest.Tag); err != nil {
+		return err
+	}
+
+	log.Printf("Done.\n")
+
+	return nil
+}
+
+// repairArchive returns the archive to re-extract the release of manifest
+// from. That is the archive it was installed from while its checksum still
+// matches, or else a fresh download of the release, which has to match the
+// same checksum.
+func repairArchive(provider distribution.Provider, manifest *installManifest) (string, error) {
+	if manifest.Archive != constants.EMPTY && manifest.ArchiveSHA256 != constants.EMPTY {
+		digest, err := fileDigest(manifest.Archive)
+		if err == nil && strings.EqualFold(digest, manifest.ArchiveSHA256) {
+			log.Printf("Using cached archive[%s].", manifest.Archive)
+			return manifest.Archive, nil
+		}
+
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Cached archive[%s] cannot be used, downloading it again.", manifest.Archive)
+		}
+	}
+
+	release, err := provider.ReleaseByTag(manifest.Tag)
+	if err != nil {
+		return "", fmt.Errorf("could not get release[%s]: %w", manifest.Tag, err)
+	}
+
+	archivePath, err := downloadRelease(provider, release)
+	if err != nil {
+		return "", err
+	}
+
+	// The release has to be re-extracted from the very archive it was
+	// installed from, not one that was replaced since.
+	if manifest.ArchiveSHA256 != constants.EMPTY {
+		digest, err := fileDigest(archivePath)
+		if err != nil {
+			return "", fmt.Errorf("could not hash archive[%s]: %w", archivePath, err)
+		}
+
+		if !strings.EqualFold(digest, manifest.ArchiveSHA256) {
+			os.Remove(archivePath)
+			return "", fmt.Errorf("downloaded archive[%s] has SHA-256 %s, but release[%s] was installed from an archive with SHA-256 %s",
+				archivePath, digest, manifest.Tag, manifest.ArchiveSHA256)
+		}
+	}
+
+	return archivePath, nil
+}
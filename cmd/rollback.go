@@ -0,0 +1,149 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// rollbackCmd represents the rollback command
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [tag]",
+	Short: constants.ROLLBACK_SHORT_DESCRIPTION,
+	Long:  constants.ROLLBACK_LONG_DESCRIPTION,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rollback(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+}
+
+func rollback(_ *cobra.Command, args []string) {
+	provider := currentProvider()
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+
+	lock, err := lockUpdates()
+	if err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+	defer lock.Release()
+
+	versions, err := keptVersions(binPath)
+	if err != nil {
+		log.Fatalf("%s: Could not list kept versions: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	if len(versions) == 0 {
+		log.Fatalf("%s: No previous versions are kept in [%s].\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), versionsDirectory(binPath))
+		os.Exit(1)
+	}
+
+	// Without a tag, go back to the most recently replaced version.
+	target := &versions[0]
+	if len(args) > 0 {
+		target = nil
+		for i := range versions {
+			if versions[i].Tag == versionDirectoryName(args[0]) {
+				target = &versions[i]
+				break
+			}
+		}
+
+		if target == nil {
+			log.Fatalf("%s: Version[%s] is not kept locally. Use 'versions' to list the kept versions.\n",
+				color.RedString(constants.FATAL_NORMAL_CASE), args[0])
+			os.Exit(1)
+		}
+	}
+
+	state := loadState()
+	installedVersion := state.InstalledRelease
+	if target.Tag == installedVersion {
+		log.Printf("Version[%s] is already installed.", installedVersion)
+		return
+	}
+
+	if err := validateInstall(target.Path, provider); err != nil {
+		log.Fatalf("%s: Kept version[%s] is not usable: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), target.Tag, err.Error())
+		os.Exit(1)
+	}
+
+	log.Printf("Rolling back %s...\n", provider.Name())
+	log.Println("Installed Version:", installedVersion)
+	log.Println(" Rollback Version:", target.Tag)
+
+	// A kept version is not worth deferring, so a running browser is fatal.
+	if err := waitForBrowserExit(binPath); err != nil {
+		log.Fatalf("%s: Could not roll back, %s.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	if err := swapInstall(target.Path, binPath, installedVersion); err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	recordInstall(binPath, target.Tag, state.InstalledChannel)
+
+	// An update staged in the background would replace the version that was
+	// just rolled back to on the next launch.
+	if loadState().PendingRelease != constants.EMPTY {
+		discardPendingUpdate(pendingDirectory(binPath))
+	}
+
+	// Without a hold, the next update would install the release that was
+	// just rolled back from all over again.
+	holdRelease(target.Tag)
+	log.Printf("Updates are on hold while version[%s] is installed. Use 'update --force' or 'install' to update again.\n", target.Tag)
+
+	log.Printf("Done.\n")
+}
@@ -1,202 +1,265 @@
-package cmd
-
-import (
-	"log"
-	"os"
-	"path/filepath"
-	"regexp"
-	"strings"
-	"unchrome_launcher/constants"
-	"unchrome_launcher/globals"
-	"unchrome_launcher/logger"
-
-	"github.com/fatih/color"
-	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-)
-
-var cfgFile string
-var logfile os.File
-
-// rootCmd represents the base command when called without any subcommands
-var rootCmd = &cobra.Command{
-	Use:   constants.APPLICATION_NAME_LOWERCASE,
-	Short: constants.ROOT_SHORT_DESCRIPTION,
-	Long:  constants.ROOT_LONG_DESCRIPTION,
-}
-
-// Execute adds all child commands to the root command and sets flags appropriately.
-// This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute(defaultCommand string) {
-	var commandFound bool
-	cmd := rootCmd.Commands()
-
-	for _, a := range cmd {
-		for _, b := range os.Args[1:] {
-			if a.Name() == b {
-				commandFound = true
-				break
-			}
-		}
-	}
-
-	if commandFound == false {
-		args := append([]string{defaultCommand}, os.Args[1:]...)
-		rootCmd.SetArgs(args)
-	}
-
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatalf("%s: %s\n", color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
-		os.Exit(1)
-	}
-}
-
-func init() {
-	cobra.MousetrapHelpText = constants.EMPTY
-	cobra.OnInitialize(initConfig)
-
-	cobra.AddTemplateFunc("StyleHeading", color.New(color.FgGreen).SprintFunc())
-	usageTemplate := rootCmd.UsageTemplate()
-	usageTemplate = strings.NewReplacer(
-		`Usage:`, `{{StyleHeading "Usage:"}}`,
-		`Aliases:`, `{{StyleHeading "Aliases:"}}`,
-		`Available Commands:`, `{{StyleHeading "Available Commands:"}}`,
-		`Global Flags:`, `{{StyleHeading "Global Flags:"}}`,
-		// The following one steps on "Global Flags:"
-		`Flags:`, `{{StyleHeading "Flags:"}}`,
-	).Replace(usageTemplate)
-	re := regexp.MustCompile(`(?m)^Flags:\s*$`)
-	usageTemplate = re.ReplaceAllLiteralString(usageTemplate, `{{StyleHeading "Flags:"}}`)
-	rootCmd.SetUsageTemplate(usageTemplate)
-	rootCmd.SetOut(color.Output)
-
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
-
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", constants.EMPTY, "config file (default is $HOME/.unchrome_launcher.yaml)")
-
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.PersistentFlags().Bool("help", false, constants.HELP_SHORT_DESCRIPTION)
-}
-
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	// Find home directory.
-	home, err := os.UserHomeDir()
-	cobra.CheckErr(err)
-
-	if cfgFile != constants.EMPTY {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Add the XDG_CONFIG_HOME directory to the search path, if configured.
-		xdgConfigHome, found := os.LookupEnv("XDG_CONFIG_HOME")
-		if found {
-			xdgConfigPath := filepath.Join(xdgConfigHome, constants.APPLICATION_NAME_LOWERCASE)
-			_, err := os.Stat(xdgConfigPath)
-			if os.IsNotExist(err) {
-				// Do nothing.
-			} else {
-				viper.AddConfigPath(xdgConfigPath)
-			}
-		}
-
-		// Add the user's home directory to the search path.
-		viper.AddConfigPath(home)
-
-		// Add the Unchrome Launcher configuration file and extension type.
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".unchrome_launcher")
-	}
-
-	// Read in environment variables that match.
-	viper.AutomaticEnv()
-
-	// Set various defaults.
-	viper.SetDefault(constants.DEBUG, false)
-	viper.SetDefault(constants.PAUSE_AFTER_RUN, false)
-	viper.SetDefault(constants.PAUSE_ON_UPDATE, false)
-	viper.SetDefault(constants.BIN_DIRECTORY, filepath.Join(".", "bin"))
-	viper.SetDefault(constants.DOWNLOAD_DIRECTORY, filepath.Join(".", "download"))
-	viper.SetDefault(constants.PROFILE_DIRECTORY, filepath.Join(".", "profile"))
-	viper.SetDefault(constants.INSTALLED_VERSION, constants.EMPTY)
-	viper.SetDefault(constants.CHROME_DISTRIBUTION, constants.UNGOOGLED_CHROMIUM_DISTRIBUTION)
-	viper.SetDefault(constants.CHROME_COMMAND_LINE_OPTIONS, "--no-default-browser-check")
-
-	// Read the configuration file.
-	err = viper.ReadInConfig()
-	if err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// No config file, just use defaults.
-			viper.SafeWriteConfig()
-			viper.ReadInConfig()
-			log.Printf("%s: Unable to load config file, writing default values to [%s].\n\n",
-				color.HiBlueString(constants.INFO_NORMAL_CASE), viper.ConfigFileUsed())
-		} else {
-			log.Fatalf("%s: Error reading config file: %s\n",
-				color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
-			os.Exit(1)
-		}
-	}
-
-	// If debugging is set, log everything to the log file.
-	if viper.GetBool(constants.DEBUG) {
-		logger.EnableFileLogging()
-	}
-
-	// Make sure the CHROME_DISTRIBUTION is set to one of our supported distributions.
-	distribution := viper.GetString(constants.CHROME_DISTRIBUTION)
-	if strings.EqualFold(distribution, constants.UNGOOGLED_CHROMIUM_DISTRIBUTION) == false &&
-		strings.EqualFold(distribution, constants.UNGOOGLED_WINCHROME_DISTRIBUTION) == false &&
-		strings.EqualFold(distribution, constants.CROMITE_DISTRIBUTION) == false {
-		log.Fatalf("%s: Unsupported distribution[%s] found. Valid distributions are '%s', '%s',and '%s'.\n",
-			color.RedString(constants.FATAL_NORMAL_CASE), distribution,
-			constants.UNGOOGLED_CHROMIUM_DISTRIBUTION, constants.UNGOOGLED_WINCHROME_DISTRIBUTION,
-			constants.CROMITE_DISTRIBUTION)
-		os.Exit(1)
-	}
-
-	// Use the global ExeDir to make sure the necessary directories exist. If
-	// they do not exist, they are created.
-	if viper.GetBool(constants.DEBUG) {
-		log.Println("Executable directory:", globals.ExeDir)
-	}
-
-	// Make sure the DOWNLOAD_DIRECTORY exists.
-	downloadDirectory := filepath.Join(globals.ExeDir, viper.GetString(constants.DOWNLOAD_DIRECTORY))
-	_, err = os.Stat(downloadDirectory)
-	if os.IsNotExist(err) {
-		err := os.MkdirAll(downloadDirectory, 0755)
-		if err != nil {
-			log.Fatalf("%s: Failed to create download directory[%s]. Error[%s]\n",
-				color.RedString(constants.FATAL_NORMAL_CASE), downloadDirectory, err.Error())
-			os.Exit(1)
-		}
-	}
-
-	// Make sure the PROFILE_DIRECTORY exists.
-	profileDirectory := filepath.Join(globals.ExeDir, viper.GetString(constants.PROFILE_DIRECTORY))
-	_, err = os.Stat(profileDirectory)
-	if os.IsNotExist(err) {
-		err := os.MkdirAll(profileDirectory, 0755)
-		if err != nil {
-			log.Fatalf("%s: Failed to create profile directory[%s]. Error[%s]\n",
-				color.RedString(constants.FATAL_NORMAL_CASE), profileDirectory, err.Error())
-			os.Exit(1)
-		}
-	}
-
-	// Make sure the BIN_DIRECTORY exists.
-	binDirectory := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
-	_, err = os.Stat(binDirectory)
-	if os.IsNotExist(err) {
-		err := os.MkdirAll(binDirectory, 0755)
-		if err != nil {
-			log.Fatalf("%s: Failed to create bin directory[%s]. Error[%s]\n",
-				color.RedString(constants.FATAL_NORMAL_CASE), binDirectory, err.Error())
-			os.Exit(1)
-		}
-	}
-}
+package cmd
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unchrome_launcher/constants"
+	"unchrome_launcher/distribution"
+	"unchrome_launcher/globals"
+	"unchrome_launcher/logger"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var cfgFile string
+var logfile os.File
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   constants.APPLICATION_NAME_LOWERCASE,
+	Short: constants.ROOT_SHORT_DESCRIPTION,
+	Long:  constants.ROOT_LONG_DESCRIPTION,
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute(defaultCommand string) {
+	var commandFound bool
+	cmd := rootCmd.Commands()
+
+	for _, a := range cmd {
+		for _, b := range os.Args[1:] {
+			if a.Name() == b {
+				commandFound = true
+				break
+			}
+		}
+	}
+
+	if commandFound == false {
+		args := append([]string{defaultCommand}, os.Args[1:]...)
+		rootCmd.SetArgs(args)
+	}
+
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatalf("%s: %s\n", color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+}
+
+func init() {
+	cobra.MousetrapHelpText = constants.EMPTY
+	cobra.OnInitialize(initConfig)
+
+	cobra.AddTemplateFunc("StyleHeading", color.New(color.FgGreen).SprintFunc())
+	usageTemplate := rootCmd.UsageTemplate()
+	usageTemplate = strings.NewReplacer(
+		`Usage:`, `{{StyleHeading "Usage:"}}`,
+		`Aliases:`, `{{StyleHeading "Aliases:"}}`,
+		`Available Commands:`, `{{StyleHeading "Available Commands:"}}`,
+		`Global Flags:`, `{{StyleHeading "Global Flags:"}}`,
+		// The following one steps on "Global Flags:"
+		`Flags:`, `{{StyleHeading "Flags:"}}`,
+	).Replace(usageTemplate)
+	re := regexp.MustCompile(`(?m)^Flags:\s*$`)
+	usageTemplate = re.ReplaceAllLiteralString(usageTemplate, `{{StyleHeading "Flags:"}}`)
+	rootCmd.SetUsageTemplate(usageTemplate)
+	rootCmd.SetOut(color.Output)
+
+	// Here you will define your flags and configuration settings.
+	// Cobra supports persistent flags, which, if defined here,
+	// will be global for your application.
+
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", constants.EMPTY, "config file (default is $HOME/.unchrome_launcher.yaml)")
+	rootCmd.PersistentFlags().Bool("allow-downgrade", false, "install the release even if it is older than the installed version")
+	viper.BindPFlag(constants.ALLOW_DOWNGRADE, rootCmd.PersistentFlags().Lookup("allow-downgrade"))
+	rootCmd.PersistentFlags().Bool(constants.LENIENT, false, "install even if some files of the archive cannot be extracted")
+	viper.BindPFlag(constants.LENIENT, rootCmd.PersistentFlags().Lookup(constants.LENIENT))
+
+	// Cobra also supports local flags, which will only run
+	// when this action is called directly.
+	rootCmd.PersistentFlags().Bool("help", false, constants.HELP_SHORT_DESCRIPTION)
+}
+
+// initConfig reads in config file and ENV variables if set.
+func initConfig() {
+	// Find home directory.
+	home, err := os.UserHomeDir()
+	cobra.CheckErr(err)
+
+	if cfgFile != constants.EMPTY {
+		// Use config file from the flag.
+		viper.SetConfigFile(cfgFile)
+	} else {
+		// Add the XDG_CONFIG_HOME directory to the search path, if configured.
+		xdgConfigHome, found := os.LookupEnv("XDG_CONFIG_HOME")
+		if found {
+			xdgConfigPath := filepath.Join(xdgConfigHome, constants.APPLICATION_NAME_LOWERCASE)
+			_, err := os.Stat(xdgConfigPath)
+			if os.IsNotExist(err) {
+				// Do nothing.
+			} else {
+				viper.AddConfigPath(xdgConfigPath)
+			}
+		}
+
+		// Add the user's home directory to the search path.
+		viper.AddConfigPath(home)
+
+		// Add the Unchrome Launcher configuration file and extension type.
+		viper.SetConfigType("yaml")
+		viper.SetConfigName(".unchrome_launcher")
+	}
+
+	// Read in environment variables that match.
+	viper.AutomaticEnv()
+
+	// Set various defaults.
+	viper.SetDefault(constants.DEBUG, false)
+	viper.SetDefault(constants.ALLOW_DOWNGRADE, false)
+	viper.SetDefault(constants.LENIENT, false)
+	viper.SetDefault(constants.OFFLINE, false)
+	viper.SetDefault(constants.CHECK_INTERVAL, "0s")
+	viper.SetDefault(constants.BACKGROUND_UPDATE, false)
+	viper.SetDefault(constants.PAUSE_AFTER_RUN, false)
+	viper.SetDefault(constants.PAUSE_ON_UPDATE, false)
+	viper.SetDefault(constants.REQUIRE_CHECKSUM, false)
+	viper.SetDefault(constants.RUNNING_BROWSER_ACTION, constants.RUNNING_BROWSER_ABORT)
+	viper.SetDefault(constants.RUNNING_BROWSER_TIMEOUT, "5m")
+	viper.SetDefault(constants.UPDATE_LOCK_TIMEOUT, "0s")
+	viper.SetDefault(constants.HTTP_CONNECT_TIMEOUT, "30s")
+	viper.SetDefault(constants.HTTP_READ_TIMEOUT, "60s")
+	viper.SetDefault(constants.HTTP_RETRIES, 3)
+	viper.SetDefault(constants.ARCHIVE_MAX_SIZE_MB, 8192)
+	viper.SetDefault(constants.ARCHIVE_MAX_FILE_SIZE_MB, 2048)
+	viper.SetDefault(constants.ARCHIVE_MAX_ENTRIES, 100000)
+	viper.SetDefault(constants.ARCHIVE_MAX_RATIO, 100)
+	viper.SetDefault(constants.BIN_DIRECTORY, filepath.Join(".", "bin"))
+	viper.SetDefault(constants.KEEP_VERSIONS, 2)
+	viper.SetDefault(constants.DOWNLOAD_DIRECTORY, filepath.Join(".", "download"))
+	viper.SetDefault(constants.PROFILE_DIRECTORY, filepath.Join(".", "profile"))
+	viper.SetDefault(constants.CHANNEL, constants.STABLE_CHANNEL)
+	viper.SetDefault(constants.CHROME_DISTRIBUTION, constants.UNGOOGLED_CHROMIUM_DISTRIBUTION)
+	viper.SetDefault(constants.CHROME_COMMAND_LINE_OPTIONS, "--no-default-browser-check")
+	viper.SetDefault(constants.VERSION_CONSTRAINT, constants.EMPTY)
+
+	// Read the configuration file.
+	err = viper.ReadInConfig()
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			// No config file, just use defaults.
+			viper.SafeWriteConfig()
+			viper.ReadInConfig()
+			log.Printf("%s: Unable to load config file, writing default values to [%s].\n\n",
+				color.HiBlueString(constants.INFO_NORMAL_CASE), viper.ConfigFileUsed())
+		} else {
+			log.Fatalf("%s: Error reading config file: %s\n",
+				color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// If debugging is set, log everything to the log file.
+	if viper.GetBool(constants.DEBUG) {
+		logger.EnableFileLogging()
+	}
+
+	// Make sure the CHROME_DISTRIBUTION is set to one of our supported distributions.
+	currentProvider()
+
+	// Make sure the ARCHIVE_STRIP_COMPONENTS of the distribution is valid.
+	archiveStripComponents(currentProvider())
+
+	// Make sure the CHANNEL is set to one of our supported channels.
+	releaseChannel()
+
+	// Make sure the VERSION_CONSTRAINT, if any, can be parsed.
+	versionConstraint()
+
+	// Make sure the CHECK_INTERVAL can be parsed.
+	checkInterval()
+
+	// Make sure the RUNNING_BROWSER_ACTION and RUNNING_BROWSER_TIMEOUT are valid.
+	runningBrowserAction()
+	runningBrowserTimeout()
+
+	// Make sure the UPDATE_LOCK_TIMEOUT can be parsed.
+	updateLockTimeout()
+
+	// Make sure the HTTP_CONNECT_TIMEOUT, HTTP_READ_TIMEOUT and HTTP_RETRIES
+	// are valid, and use them for every request.
+	configureHTTPClient()
+
+	// Use the global ExeDir to make sure the necessary directories exist. If
+	// they do not exist, they are created.
+	if viper.GetBool(constants.DEBUG) {
+		log.Println("Executable directory:", globals.ExeDir)
+	}
+
+	// Make sure the DOWNLOAD_DIRECTORY exists.
+	downloadDirectory := filepath.Join(globals.ExeDir, viper.GetString(constants.DOWNLOAD_DIRECTORY))
+	_, err = os.Stat(downloadDirectory)
+	if os.IsNotExist(err) {
+		err := os.MkdirAll(downloadDirectory, 0755)
+		if err != nil {
+			log.Fatalf("%s: Failed to create download directory[%s]. Error[%s]\n",
+				color.RedString(constants.FATAL_NORMAL_CASE), downloadDirectory, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// Cache GitHub API responses in the DOWNLOAD_DIRECTORY, and authenticate
+	// the requests if a token is configured. Because of AutomaticEnv, the
+	// GITHUB_TOKEN environment variable works as well. The token has no
+	// default on purpose, so that a token from the environment is never
+	// written to a new configuration file.
+	distribution.CacheDirectory = filepath.Join(downloadDirectory, constants.GITHUB_CACHE_DIRECTORY)
+	distribution.GitHubToken = viper.GetString(constants.GITHUB_TOKEN)
+
+	// Make sure the PROFILE_DIRECTORY exists.
+	profileDirectory := filepath.Join(globals.ExeDir, viper.GetString(constants.PROFILE_DIRECTORY))
+	_, err = os.Stat(profileDirectory)
+	if os.IsNotExist(err) {
+		err := os.MkdirAll(profileDirectory, 0755)
+		if err != nil {
+			log.Fatalf("%s: Failed to create profile directory[%s]. Error[%s]\n",
+				color.RedString(constants.FATAL_NORMAL_CASE), profileDirectory, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// Move the installed release out of the configuration file, where older
+	// versions recorded it.
+	migrateState()
+
+	// Make sure the BIN_DIRECTORY exists.
+	binDirectory := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+
+	// A launcher holding the update lock may be in the middle of a swap, in
+	// which case the BIN_DIRECTORY is left alone.
+	lock, err := tryLockUpdates()
+	defer lock.Release()
+	if errors.Is(err, errLockHeld) {
+		return
+	}
+
+	// Put the previous install back if an update was interrupted mid-swap,
+	// before an empty BIN_DIRECTORY is created in its place.
+	recoverInterruptedInstall(binDirectory)
+
+	_, err = os.Stat(binDirectory)
+	if os.IsNotExist(err) {
+		err := os.MkdirAll(binDirectory, 0755)
+		if err != nil {
+			log.Fatalf("%s: Failed to create bin directory[%s]. Error[%s]\n",
+				color.RedString(constants.FATAL_NORMAL_CASE), binDirectory, err.Error())
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,467 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"unchrome_launcher/archive"
+	"unchrome_launcher/constants"
+	"unchrome_launcher/distribution"
+
+	"github.com/fatih/color"
+	"github.com/spf13/viper"
+)
+
+// stagingDirectory returns the sibling directory of binPath that archives are
+// extracted into before they are swapped into place.
+func stagingDirectory(binPath string) string {
+	return filepath.Clean(binPath) + ".staging"
+}
+
+// previousDirectory returns the sibling directory of binPath that holds the
+// previous install while the new one is swapped in.
+func previousDirectory(binPath string) string {
+	return filepath.Clean(binPath) + ".previous"
+}
+
+// unmanagedDirectory returns the sibling directory of binPath that keeps an
+// install made before the manifest existed, once it has been replaced.
+func unmanagedDirectory(binPath string) string {
+	return filepath.Clean(binPath) + ".before-manifest"
+}
+
+// versionsDirectory returns the sibling directory of binPath that keeps the
+// previously installed versions, one sub directory per release tag.
+func versionsDirectory(binPath string) string {
+	return filepath.Clean(binPath) + ".versions"
+}
+
+// versionDirectory returns the directory a kept release is stored in.
+func versionDirectory(binPath string, tag string) string {
+	return filepath.Join(versionsDirectory(binPath), versionDirectoryName(tag))
+}
+
+// versionDirectoryName makes a release tag safe to use as a directory name.
+func versionDirectoryName(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, tag)
+}
+
+// keptVersion is a previously installed release kept next to the bin
+// directory.
+type keptVersion struct {
+	Tag  string
+	Path string
+	Time time.Time
+}
+
+// installArchive extracts archivePath, the archive of release tag, into a
+// staging directory next to binPath, validates the result and then swaps it
+// in with a rename. The previous install is kept until the swap has
+// succeeded, and is put back if it fails.
+func installArchive(archivePath string, binPath string, provider distribution.Provider, installedVersion string, tag string) error {
+	binPath = filepath.Clean(binPath)
+
+	stagingPath, err := stageArchive(archivePath, binPath, provider, tag)
+	if err != nil {
+		return err
+	}
+
+	if err := swapInstall(stagingPath, binPath, installedVersion); err != nil {
+		os.RemoveAll(stagingPath)
+		return err
+	}
+
+	return nil
+}
+
+// installOrDefer installs archivePath like installArchive, once the browser
+// is no longer running from binPath. When it is still running and the
+// RUNNING_BROWSER_ACTION is "defer", the archive is staged as a pending
+// update for tag instead, and true is returned.
+func installOrDefer(archivePath string, binPath string, provider distribution.Provider, installedVersion string, tag string, channel string) (bool, error) {
+	if err := waitForBrowserExit(binPath); err != nil {
+		if !isBrowserRunningError(err) || runningBrowserAction() != constants.RUNNING_BROWSER_DEFER {
+			return false, err
+		}
+
+		if _, stageErr := stagePendingUpdate(archivePath, binPath, provider, tag, channel); stageErr != nil {
+			return false, stageErr
+		}
+
+		log.Printf("%s: Not installing now, %s. Release[%s] will be installed on the next launch.\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), err.Error(), tag)
+		return true, nil
+	}
+
+	return false, installArchive(archivePath, binPath, provider, installedVersion, tag)
+}
+
+// stageArchive extracts archivePath, the archive of release tag, into the
+// staging directory next to binPath and validates it. The staging directory
+// is returned on success and removed on failure.
+func stageArchive(archivePath string, binPath string, provider distribution.Provider, tag string) (string, error) {
+	stagingPath := stagingDirectory(binPath)
+
+	// Remove whatever an interrupted install may have left behind.
+	if err := os.RemoveAll(stagingPath); err != nil {
+		return "", fmt.Errorf("could not remove staging directory[%s]: %w", stagingPath, err)
+	}
+
+	if err := os.MkdirAll(stagingPath, 0755); err != nil {
+		return "", fmt.Errorf("could not create staging directory[%s]: %w", stagingPath, err)
+	}
+
+	options := archive.Options{
+		StripComponents: archiveStripComponents(provider),
+		Executable:      provider.Executable(),
+		Limits:          archiveLimits(),
+		Lenient:         viper.GetBool(constants.LENIENT),
+	}
+
+	if err := archive.Extract(archivePath, stagingPath, options); err != nil {
+		os.RemoveAll(stagingPath)
+
+		var limitError *archive.LimitError
+		if errors.As(err, &limitError) {
+			return "", fmt.Errorf("refusing to install [%s], %w. Raise the limit in the configuration file if the archive can be trusted", archivePath, err)
+		}
+
+		return "", err
+	}
+
+	if err := validateInstall(stagingPath, provider); err != nil {
+		os.RemoveAll(stagingPath)
+		return "", err
+	}
+
+	// Record what was extracted, so that the next update can tell these files
+	// apart from the ones the user added, and verify can tell if any of them
+	// went missing or changed.
+	if err := writeManifest(stagingPath, provider.Name(), tag, archivePath); err != nil {
+		os.RemoveAll(stagingPath)
+		return "", err
+	}
+
+	return stagingPath, nil
+}
+
+// archiveLimits returns the configured limits on what an archive may
+// extract to.
+func archiveLimits() archive.Limits {
+	return archive.Limits{
+		MaxSize:     viper.GetInt64(constants.ARCHIVE_MAX_SIZE_MB) << 20,
+		MaxEntries:  viper.GetInt(constants.ARCHIVE_MAX_ENTRIES),
+		MaxFileSize: viper.GetInt64(constants.ARCHIVE_MAX_FILE_SIZE_MB) << 20,
+		MaxRatio:    viper.GetInt64(constants.ARCHIVE_MAX_RATIO),
+	}
+}
+
+// archiveStripComponents returns the number of leading path elements removed
+// from the entries of provider's archives, or archive.AutoStripComponents to
+// detect it. It is configured for every distribution on its own, as
+// ARCHIVE_STRIP_COMPONENTS.<distribution>, and falls back to the layout of
+// the distribution when not configured.
+func archiveStripComponents(provider distribution.Provider) int {
+	values := viper.GetStringMapString(constants.ARCHIVE_STRIP_COMPONENTS)
+	value := strings.TrimSpace(values[strings.ToLower(provider.Name())])
+	if value == constants.EMPTY {
+		return provider.Layout().StripComponents
+	} else if strings.EqualFold(value, constants.AUTO_STRIP_COMPONENTS) {
+		return archive.AutoStripComponents
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		log.Fatalf("%s: Invalid %s[%s] for distribution[%s], use '%s' or a number of directories such as '1'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), constants.ARCHIVE_STRIP_COMPONENTS, value,
+			provider.Name(), constants.AUTO_STRIP_COMPONENTS)
+		os.Exit(1)
+	}
+
+	return count
+}
+
+// swapInstall renames newPath to binPath. Files the user added to the live
+// install are copied into newPath, while the files of the old version are
+// left behind with it. The live install is moved aside first and restored if
+// the rename fails. Once the new install is in place, the old one is kept as
+// installedVersion, or removed when versions are not being kept.
+func swapInstall(newPath string, binPath string, installedVersion string) error {
+	previousPath := previousDirectory(binPath)
+
+	// Without a manifest, the files the user added cannot be told apart from
+	// those of the old version, so the old install is kept as a whole.
+	unmanaged := isUnmanagedInstall(binPath)
+
+	if err := carryOverUntrackedFiles(binPath, newPath); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(previousPath); err != nil {
+		return fmt.Errorf("could not remove previous directory[%s]: %w", previousPath, err)
+	}
+
+	// Move the live install out of the way, keeping it until the new one is in
+	// place.
+	hadPrevious := false
+	if _, err := os.Stat(binPath); err == nil {
+		if err := os.Rename(binPath, previousPath); err != nil {
+			return fmt.Errorf("could not move [%s] out of the way: %w", binPath, err)
+		}
+		hadPrevious = true
+	}
+
+	if err := os.Rename(newPath, binPath); err != nil {
+		if hadPrevious {
+			if rollbackErr := os.Rename(previousPath, binPath); rollbackErr != nil {
+				return fmt.Errorf("could not swap in new install: %v, and could not restore the previous install from [%s]: %w",
+					err, previousPath, rollbackErr)
+			}
+		}
+
+		return fmt.Errorf("could not swap in new install: %w", err)
+	}
+
+	if hadPrevious {
+		if unmanaged && keepUnmanagedInstall(binPath) {
+			return nil
+		}
+
+		retirePreviousInstall(binPath, installedVersion)
+	}
+
+	return nil
+}
+
+// keepUnmanagedInstall moves the install that was just replaced, one made
+// before the manifest existed, to the unmanaged directory rather than
+// retiring it, so that no file the user added to it is lost. It reports if
+// the install was kept.
+func keepUnmanagedInstall(binPath string) bool {
+	previousPath := previousDirectory(binPath)
+	unmanagedPath := unmanagedDirectory(binPath)
+
+	if _, err := os.Stat(unmanagedPath); err == nil {
+		log.Printf("%s: Could not keep previous install[%s], [%s] already exists.\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), previousPath, unmanagedPath)
+		return false
+	}
+
+	if err := os.Rename(previousPath, unmanagedPath); err != nil {
+		log.Printf("%s: Could not keep previous install[%s] as [%s]: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), previousPath, unmanagedPath, err.Error())
+		return false
+	}
+
+	log.Printf("%s: The previous install has no manifest, so files you added to it could not be told apart from the browser's "+
+		"and were not copied into the new install. It has been kept in [%s]. Copy anything you added from there, then remove it.\n",
+		color.YellowString(constants.WARNING_NORMAL_CASE), unmanagedPath)
+
+	return true
+}
+
+// retirePreviousInstall moves the install that was just replaced into the
+// versions directory, then prunes the versions directory down to the
+// configured number of kept versions.
+func retirePreviousInstall(binPath string, installedVersion string) {
+	previousPath := previousDirectory(binPath)
+	keep := viper.GetInt(constants.KEEP_VERSIONS)
+
+	if keep > 0 && installedVersion != constants.EMPTY {
+		versionPath := versionDirectory(binPath, installedVersion)
+
+		err := os.MkdirAll(versionsDirectory(binPath), 0755)
+		if err == nil {
+			err = os.RemoveAll(versionPath)
+		}
+		if err == nil {
+			err = os.Rename(previousPath, versionPath)
+		}
+
+		if err == nil {
+			// Directory times are not reliable across renames, so stamp the kept
+			// version with the time it was retired to order it when pruning.
+			now := time.Now()
+			os.Chtimes(versionPath, now, now)
+		} else {
+			log.Printf("%s: Could not keep previous install[%s] as [%s]: %s\n",
+				color.YellowString(constants.WARNING_NORMAL_CASE), previousPath, versionPath, err.Error())
+		}
+	}
+
+	if _, err := os.Stat(previousPath); err == nil {
+		if err := os.RemoveAll(previousPath); err != nil {
+			log.Printf("%s: Could not remove previous install[%s]: %s\n",
+				color.YellowString(constants.WARNING_NORMAL_CASE), previousPath, err.Error())
+		}
+	}
+
+	pruneVersions(binPath, keep)
+}
+
+// keptVersions lists the versions kept next to binPath, newest first.
+func keptVersions(binPath string) ([]keptVersion, error) {
+	entries, err := os.ReadDir(versionsDirectory(binPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var versions []keptVersion
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, keptVersion{
+			Tag:  entry.Name(),
+			Path: filepath.Join(versionsDirectory(binPath), entry.Name()),
+			Time: info.ModTime(),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Time.After(versions[j].Time)
+	})
+
+	return versions, nil
+}
+
+// pruneVersions removes all but the newest keep versions.
+func pruneVersions(binPath string, keep int) {
+	versions, err := keptVersions(binPath)
+	if err != nil {
+		log.Printf("%s: Could not list kept versions: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), err.Error())
+		return
+	}
+
+	if keep < 0 {
+		keep = 0
+	}
+
+	for i := keep; i < len(versions); i++ {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Removing kept version[%s].", versions[i].Path)
+		}
+
+		if err := os.RemoveAll(versions[i].Path); err != nil {
+			log.Printf("%s: Could not remove kept version[%s]: %s\n",
+				color.YellowString(constants.WARNING_NORMAL_CASE), versions[i].Path, err.Error())
+		}
+	}
+}
+
+// validateInstall makes sure an extracted tree contains the distribution's
+// executable before it is allowed to replace the live install.
+func validateInstall(path string, provider distribution.Provider) error {
+	executable := filepath.Join(path, provider.Executable())
+
+	info, err := os.Stat(executable)
+	if err != nil {
+		return fmt.Errorf("extracted archive is missing the executable[%s]: %w. "+
+			"If the archive keeps its files in a different directory, set %s for %s in the configuration file",
+			provider.Executable(), err, constants.ARCHIVE_STRIP_COMPONENTS, provider.Name())
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("extracted archive executable[%s] is a directory", provider.Executable())
+	}
+
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("extracted archive executable[%s] is not executable", provider.Executable())
+	}
+
+	return nil
+}
+
+// recoverInterruptedInstall puts the previous install back when an earlier
+// swap was interrupted after the live install had been moved aside, or
+// retires it the way swapInstall would have when the swap itself completed.
+func recoverInterruptedInstall(binPath string) {
+	binPath = filepath.Clean(binPath)
+	previousPath := previousDirectory(binPath)
+
+	if _, err := os.Stat(previousPath); err != nil {
+		return
+	}
+
+	if _, err := os.Stat(binPath); os.IsNotExist(err) {
+		log.Printf("%s: Restoring previous install from [%s] after an interrupted update.\n",
+			color.HiBlueString(constants.INFO_NORMAL_CASE), previousPath)
+
+		if err := os.Rename(previousPath, binPath); err != nil {
+			log.Printf("%s: Could not restore previous install[%s]: %s\n",
+				color.YellowString(constants.WARNING_NORMAL_CASE), previousPath, err.Error())
+		}
+		return
+	}
+
+	// The swap completed, only retiring the previous install was missed. Its
+	// manifest tells which release it was, while one without a manifest may
+	// hold files the user added and is kept whole.
+	manifest, err := readManifest(previousPath)
+	if os.IsNotExist(err) {
+		keepUnmanagedInstall(binPath)
+		return
+	} else if err != nil {
+		log.Printf("%s: Could not read the manifest of previous install[%s], leaving it in place: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), previousPath, err.Error())
+		return
+	}
+
+	log.Printf("%s: Retiring previous install[%s] after an interrupted update.\n",
+		color.HiBlueString(constants.INFO_NORMAL_CASE), previousPath)
+
+	retirePreviousInstall(binPath, manifest.Tag)
+}
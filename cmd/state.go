@@ -0,0 +1,190 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/viper"
+)
+
+// launcherState is what the launcher records about the install it manages.
+// It is kept in its own file, so that the user's configuration file is never
+// rewritten by an update.
+type launcherState struct {
+	InstalledRelease string `json:"installed_release"`
+	InstalledChannel string `json:"installed_channel"`
+	InstalledAt      string `json:"installed_at"`
+	ArchiveSHA256    string `json:"archive_sha256"`
+	LastChecked      string `json:"last_checked"`
+	PendingRelease   string `json:"pending_release"`
+	PendingChannel   string `json:"pending_channel"`
+	HeldRelease      string `json:"held_release"`
+}
+
+// statePath returns the path of the state file, which sits next to the bin
+// directory so that configurations with their own bin directory also keep
+// their own state.
+func statePath() string {
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+	return filepath.Clean(binPath) + ".state.json"
+}
+
+// loadState reads the state file. A missing state file is an empty state,
+// and so is one that cannot be read, after a warning, so that a damaged
+// state file never keeps the browser from starting.
+func loadState() *launcherState {
+	state := &launcherState{}
+
+	data, err := os.ReadFile(statePath())
+	if os.IsNotExist(err) {
+		return state
+	} else if err == nil {
+		err = json.Unmarshal(data, state)
+	}
+
+	if err != nil {
+		log.Printf("%s: Could not read state file[%s], starting over: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), statePath(), err.Error())
+		return &launcherState{}
+	}
+
+	return state
+}
+
+// saveState applies change to the state file. The file is read again first,
+// so that changes made by another launcher in the meantime are kept, and it
+// is replaced with a rename, so that an interruption cannot truncate it.
+func saveState(change func(state *launcherState)) {
+	state := loadState()
+	change(state)
+
+	if err := writeState(state); err != nil {
+		log.Printf("%s: Could not write state file[%s]: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), statePath(), err.Error())
+	}
+}
+
+// writeState writes state to the state file.
+func writeState(state *launcherState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := statePath()
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return nil
+}
+
+// holdRelease keeps update from replacing tag, the release rolled back to,
+// until the user asks for an update with 'update --force' or 'install'.
+func holdRelease(tag string) {
+	saveState(func(state *launcherState) {
+		state.HeldRelease = tag
+	})
+}
+
+// releaseHold lets update replace the installed version again, after the
+// user installed a release of their choosing.
+func releaseHold() {
+	if loadState().HeldRelease == constants.EMPTY {
+		return
+	}
+
+	saveState(func(state *launcherState) {
+		state.HeldRelease = constants.EMPTY
+	})
+}
+
+// recordInstall records tag from channel as the release installed in
+// binPath, along with the checksum of the archive its manifest names.
+func recordInstall(binPath string, tag string, channel string) {
+	archiveDigest := constants.EMPTY
+	if manifest, err := readManifest(binPath); err == nil {
+		archiveDigest = manifest.ArchiveSHA256
+	}
+
+	saveState(func(state *launcherState) {
+		state.InstalledRelease = tag
+		state.InstalledChannel = channel
+		state.InstalledAt = time.Now().Format(time.RFC3339)
+		state.ArchiveSHA256 = archiveDigest
+	})
+}
+
+// migrateState moves the state that older versions kept in the configuration
+// file into a new state file. The configuration file is left as it is, the
+// old settings are simply no longer used.
+func migrateState() {
+	if _, err := os.Stat(statePath()); !os.IsNotExist(err) {
+		return
+	}
+
+	state := &launcherState{
+		InstalledRelease: viper.GetString(constants.INSTALLED_VERSION),
+		InstalledChannel: viper.GetString(constants.INSTALLED_CHANNEL),
+		LastChecked:      viper.GetString(constants.LAST_CHECKED),
+		PendingRelease:   viper.GetString(constants.PENDING_RELEASE),
+		PendingChannel:   viper.GetString(constants.PENDING_CHANNEL),
+	}
+
+	if *state == (launcherState{}) {
+		return
+	}
+
+	if err := writeState(state); err != nil {
+		log.Printf("%s: Could not move the installed release from [%s] to [%s]: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), viper.ConfigFileUsed(), statePath(), err.Error())
+		return
+	}
+
+	log.Printf("%s: Moved the installed release[%s] from [%s] to the state file[%s].\n\n",
+		color.HiBlueString(constants.INFO_NORMAL_CASE), state.InstalledRelease, viper.ConfigFileUsed(), statePath())
+}
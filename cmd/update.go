@@ -1,210 +1,478 @@
-/*
-Copyright © 2018-2025 Jeff Lanzarotta
-All rights reserved.
-
-Redistribution and use in source and binary forms, with or without
-modification, are permitted provided that the following conditions are met:
-
- 1. Redistributions of source code must retain the above copyright notice,
-    this list of conditions and the following disclaimer.
-
- 2. Redistributions in binary form must reproduce the above copyright notice,
-    this list of conditions and the following disclaimer in the documentation
-    and/or other materials provided with the distribution.
-
- 3. Neither the name of the copyright holder nor the names of its contributors
-    may be used to endorse or promote products derived from this software
-    without specific prior written permission.
-
-THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
-AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
-IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
-ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
-LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
-CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
-SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
-INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
-CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
-ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
-POSSIBILITY OF SUCH DAMAGE.
-*/
-
-package cmd
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
-	"unchrome_launcher/constants"
-
-	"github.com/fatih/color"
-	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-
-	"github.com/schollz/progressbar/v3"
-)
-
-type Release struct {
-	TagName string `json:"tag_name"`
-	Assets  []struct {
-		Name               string `json:"name"`
-		BrowserDownloadURL string `json:"browser_download_url"`
-	} `json:"assets"`
-}
-
-//var p *tea.Program
-
-type progressWriter struct {
-	total      int
-	downloaded int
-	file       *os.File
-	reader     io.Reader
-	onProgress func(float64)
-}
-
-// versionCmd represents the version command
-var updateCmd = &cobra.Command{
-	Use: "update",
-	Run: func(cmd *cobra.Command, args []string) {
-		update(cmd, args)
-	},
-}
-
-func init() {
-	rootCmd.AddCommand(updateCmd)
-}
-
-func update(_ *cobra.Command, _ []string) {
-	// Step 1: Get latest release info.
-	distribution := viper.GetString(constants.CHROME_DISTRIBUTION)
-	url := constants.EMPTY
-	assetName := constants.EMPTY
-
-	if strings.EqualFold(distribution, constants.UNGOOGLED_CHROMIUM_DISTRIBUTION) {
-		url = constants.UNGOOGLED_CHROMIUM_WINDOWS_GITHUB_URL
-		assetName = constants.UNGOOGLED_CHROMIUM_WINDOWS_ASSET_NAME
-	} else if strings.EqualFold(distribution, constants.UNGOOGLED_WINCHROME_DISTRIBUTION) {
-		url = constants.UNGOOGLED_WINCHROME_GITHUB_URL
-		assetName = constants.UNGOOGLED_WINCHROME_ASSET_NAME
-	} else {
-		url = constants.CROMITE_GITHUB_URL
-		assetName = constants.CROMITE_ASSET_NAME
-	}
-
-	if viper.GetBool(constants.DEBUG) {
-		log.Printf("Attempting to Update Distribution[%s] from URL[%s] with assetName[%s]", distribution, url, assetName)
-	}
-
-	resp, err := http.Get(url)
-	if err != nil {
-		panic(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		panic(fmt.Sprintf("GitHub API request failed: %s", resp.Status))
-	}
-
-	var release Release
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		panic(err)
-	}
-
-	var installedVersion string = viper.GetString(constants.INSTALLED_VERSION)
-	if strings.Compare(installedVersion, release.TagName) == 0 {
-		log.Printf("No need to update, you have the latest version[%s] installed.", release.TagName)
-		return
-	}
-
-	log.Printf("AUTOUPDATING %s to latest release version...\n", distribution)
-	log.Println("      Installed Version:", installedVersion)
-	log.Println("Latest Released Version:", release.TagName)
-
-	// Step 2: Find the desired asset.
-	var downloadURL string
-	for _, asset := range release.Assets {
-		if strings.HasSuffix(asset.Name, assetName) {
-			downloadURL = asset.BrowserDownloadURL
-			break
-		}
-	}
-
-	if downloadURL == constants.EMPTY {
-		panic("Asset not found in the latest release!")
-	}
-
-	// Find the directory where the Unchrome Launcher executable is located.
-	exePath, err := os.Executable()
-	if err != nil {
-		log.Fatalf("%s: %v\n",
-			color.RedString(constants.FATAL_NORMAL_CASE), err)
-		os.Exit(1)
-	}
-	exeDir := filepath.Dir(exePath)
-
-	if viper.GetBool(constants.DEBUG) {
-		log.Printf("ExeDir[%s].", exeDir)
-	}
-
-	// Construct the full download path.
-	var downloadPath string = filepath.Join(exeDir, viper.GetString(constants.DOWNLOAD_DIRECTORY))
-
-	if viper.GetBool(constants.DEBUG) {
-		log.Printf("DownloadDir[%s].", downloadPath)
-	}
-
-	filename := filepath.Base(*&downloadURL)
-	file, err := os.Create(filepath.Join(downloadPath, filename))
-	if err != nil {
-		log.Fatalf("%s: could not create file: %s.\n",
-			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
-		os.Exit(1)
-	}
-	defer file.Close() // nolint:errcheck
-
-	// Step 3: Download the asset
-	req, _ := http.NewRequest("GET", downloadURL, nil)
-	resp, _ = http.DefaultClient.Do(req)
-	defer check(resp.Body.Close)
-
-	bar := progressbar.DefaultBytes(
-		resp.ContentLength,
-		"downloading",
-	)
-	io.Copy(io.MultiWriter(file, bar), resp.Body)
-
-	// Construct the full bin path.
-	var binPath string = filepath.Join(exeDir, viper.GetString(constants.BIN_DIRECTORY), string(os.PathSeparator))
-
-	log.Printf("Unzipping [%s] into [%s]...", file.Name(), binPath)
-
-	// Step 4: Unzip the contents of the downloaded file to the BIN_DIRECTORY.
-	err = unzip(file.Name(), binPath)
-	if err != nil {
-		log.Fatalf("%s: %s\n",
-			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
-		os.Exit(1)
-	}
-
-	// Step 5: Write the new version to the configuration file.
-	viper.Set(constants.INSTALLED_VERSION, release.TagName)
-	viper.WriteConfig()
-
-	log.Printf("Done.\n")
-
-	if viper.GetBool(constants.PAUSE_ON_UPDATE) {
-		waitForKeyPress()
-	}
-}
-
-// check checks the returned error of a function.
-func check(f func() error) {
-	if err := f(); err != nil {
-		fmt.Fprintf(os.Stderr, "received error: %v\n", err)
-	}
-}
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unchrome_launcher/constants"
+	"unchrome_launcher/distribution"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+//var p *tea.Program
+
+type progressWriter struct {
+	total      int
+	downloaded int
+	file       *os.File
+	reader     io.Reader
+	onProgress func(float64)
+}
+
+// versionCmd represents the version command
+var updateCmd = &cobra.Command{
+	Use: "update",
+	Run: func(cmd *cobra.Command, args []string) {
+		update(cmd, args)
+	},
+}
+
+// forceUpdate makes update replace a version held by rollback.
+var forceUpdate bool
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+
+	updateCmd.Flags().BoolVar(&forceUpdate, "force", false, "update even if a rollback is holding the installed version")
+}
+
+func update(_ *cobra.Command, _ []string) {
+	lock, err := lockUpdates()
+	if err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+	defer lock.Release()
+
+	if err := updateDistribution(); err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	recordLastCheck()
+}
+
+// updateDistribution installs the latest release of the configured
+// distribution if it is not installed already, or repairs the installed one
+// when it is damaged. Errors are returned rather than being fatal, so that
+// callers can decide to carry on with the install they already have.
+func updateDistribution() error {
+	provider := currentProvider()
+
+	release, err := findUpdate(provider)
+	if err != nil {
+		return err
+	}
+
+	// Nothing newer to install, but files of the installed release may have
+	// gone missing since, for example when antivirus quarantined a DLL.
+	if release == nil {
+		binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+		return repairDamagedInstall(provider, binPath)
+	}
+
+	var installedVersion string = loadState().InstalledRelease
+
+	log.Printf("AUTOUPDATING %s to latest release version...\n", provider.Name())
+	log.Println("      Installed Version:", installedVersion)
+	log.Println("Latest Released Version:", release.TagName)
+
+	return installRelease(provider, release, installedVersion, releaseChannel())
+}
+
+// findUpdate returns the release that should replace the installed version,
+// or nil if there is nothing to update.
+func findUpdate(provider distribution.Provider) (*distribution.Release, error) {
+	// Step 1: Get latest release info.
+	if viper.GetBool(constants.DEBUG) {
+		log.Printf("Attempting to Update Distribution[%s]", provider.Name())
+	}
+
+	release, err := resolveRelease(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	state := loadState()
+	update, downgrade := wantsRelease(provider, state, release)
+	if !update {
+		return nil, nil
+	}
+
+	if downgrade {
+		log.Printf("%s: Downgrading from [%s] to [%s].\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), state.InstalledRelease, release.TagName)
+	}
+
+	return release, nil
+}
+
+// wantsRelease decides if release should replace the version installed
+// according to state, logging why not, and if doing so goes back to an
+// older version.
+func wantsRelease(provider distribution.Provider, state *launcherState, release *distribution.Release) (update bool, downgrade bool) {
+	var installedVersion string = state.InstalledRelease
+	if strings.Compare(installedVersion, release.TagName) == 0 {
+		log.Printf("No need to update, you have the latest version[%s] installed.", release.TagName)
+		return false, false
+	}
+
+	// A version that was rolled back to stays installed until the user asks
+	// for an update.
+	if state.HeldRelease != constants.EMPTY && state.HeldRelease == installedVersion && !forceUpdate {
+		log.Printf("Not updating to release[%s], version[%s] is on hold since the rollback. Use 'update --force' or 'install' to update again.",
+			release.TagName, installedVersion)
+		return false, false
+	}
+
+	// Going back is allowed when asked for, or when the installed version is
+	// outside the configured constraint, for example after pinning a major
+	// version.
+	if isUpToDate(provider, installedVersion, release) {
+		if !viper.GetBool(constants.ALLOW_DOWNGRADE) && satisfiesConstraint(provider, installedVersion) {
+			log.Printf("No need to update, release[%s] is not newer than the installed version[%s]. Use --allow-downgrade to install it anyway.",
+				release.TagName, installedVersion)
+			return false, false
+		}
+
+		return true, true
+	}
+
+	return true, false
+}
+
+// installRelease downloads, verifies and installs release in place of
+// installedVersion, and records it as installed from channel.
+func installRelease(provider distribution.Provider, release *distribution.Release, installedVersion string, channel string) error {
+	// Construct the full bin path.
+	var binPath string = filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+
+	// With "abort", a running browser fails the install anyway, so there is
+	// no point in downloading the release first.
+	if runningBrowserAction() == constants.RUNNING_BROWSER_ABORT {
+		if err := browserRunning(binPath); isBrowserRunningError(err) {
+			return err
+		}
+	}
+
+	archivePath, err := downloadRelease(provider, release)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Unzipping [%s] into [%s]...", archivePath, binPath)
+
+	// Step 4: Unzip the contents of the downloaded file into a staging
+	// directory and swap it in as the new BIN_DIRECTORY, unless the browser
+	// is running and the update is deferred to the next launch.
+	deferred, err := installOrDefer(archivePath, binPath, provider, installedVersion, release.TagName, channel)
+	if err != nil || deferred {
+		return err
+	}
+
+	// Step 5: Record the new version in the state file, now that the new
+	// install is in place.
+	recordInstall(binPath, release.TagName, channel)
+	releaseHold()
+
+	// An update staged in the background is superseded by this one.
+	if loadState().PendingRelease != constants.EMPTY {
+		discardPendingUpdate(pendingDirectory(binPath))
+	}
+
+	log.Printf("Done.\n")
+
+	if viper.GetBool(constants.PAUSE_ON_UPDATE) {
+		waitForKeyPress()
+	}
+
+	return nil
+}
+
+// downloadRelease downloads the distribution's asset of release into the
+// DOWNLOAD_DIRECTORY, verifies its checksum and returns its path.
+func downloadRelease(provider distribution.Provider, release *distribution.Release) (string, error) {
+	// Step 2: Find the desired asset and its published checksum.
+	asset, err := provider.SelectAsset(release)
+	if err != nil {
+		return "", err
+	}
+	downloadURL := asset.BrowserDownloadURL
+
+	expectedDigest, err := provider.ExpectedDigest(release, asset)
+	if err != nil {
+		return "", fmt.Errorf("could not get the published checksum: %w", err)
+	}
+
+	if expectedDigest == constants.EMPTY {
+		if viper.GetBool(constants.REQUIRE_CHECKSUM) {
+			return "", fmt.Errorf("no SHA-256 checksum published for asset[%s], refusing to install it", asset.Name)
+		}
+
+		log.Printf("%s: No SHA-256 checksum published for asset[%s], skipping verification.\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), asset.Name)
+	}
+
+	// Construct the full download path.
+	var downloadPath string = filepath.Join(globals.ExeDir, viper.GetString(constants.DOWNLOAD_DIRECTORY))
+
+	if viper.GetBool(constants.DEBUG) {
+		log.Printf("DownloadDir[%s].", downloadPath)
+	}
+
+	// Step 3: Download the asset, hashing it as it is written.
+	archivePath := filepath.Join(downloadPath, filepath.Base(downloadURL))
+	digest := completeDownload(archivePath, expectedDigest, asset.Size)
+	if digest == constants.EMPTY {
+		digest, err = downloadFile(downloadURL, archivePath, asset.Size)
+		if err != nil {
+			return "", fmt.Errorf("failed to download [%s]: %w", downloadURL, err)
+		}
+	}
+
+	if expectedDigest != constants.EMPTY {
+		if err := verifyDigest(archivePath, digest, expectedDigest); err != nil {
+			return "", err
+		}
+
+		log.Printf("Verified SHA-256 checksum [%s].", digest)
+	}
+
+	return archivePath, nil
+}
+
+// resolveRelease looks up the release that update would install. That is
+// the newest release of the configured CHANNEL that satisfies the
+// VERSION_CONSTRAINT, if any. Only the stable channel without a constraint
+// can use the cheaper latest release lookup.
+func resolveRelease(provider distribution.Provider) (*distribution.Release, error) {
+	channel := releaseChannel()
+	constraint := versionConstraint()
+
+	if channel == constants.STABLE_CHANNEL && constraint.IsEmpty() {
+		release, err := provider.LatestRelease()
+		if err != nil {
+			return nil, fmt.Errorf("could not get the latest release: %w", err)
+		}
+
+		return release, nil
+	}
+
+	var newest *distribution.Release
+	var newestVersion distribution.Version
+	err := provider.Releases(func(releases []distribution.Release) bool {
+		for i := range releases {
+			if releases[i].Draft || (releases[i].Prerelease && channel != constants.PRERELEASE_CHANNEL) {
+				continue
+			}
+
+			version, err := provider.ParseVersion(releases[i].TagName)
+			if err != nil {
+				if viper.GetBool(constants.DEBUG) {
+					log.Printf("Skipping release: %s", err.Error())
+				}
+				continue
+			}
+
+			if !constraint.Allows(version) {
+				if viper.GetBool(constants.DEBUG) {
+					log.Printf("Skipping release[%s], it does not satisfy the version constraint[%s].", releases[i].TagName, constraint)
+				}
+				continue
+			}
+
+			if newest == nil || version.Compare(newestVersion) > 0 {
+				release := releases[i]
+				newest = &release
+				newestVersion = version
+			}
+		}
+
+		// Releases are listed newest first, so once a page has a match the
+		// following pages only hold older ones.
+		return newest == nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get the list of releases: %w", err)
+	}
+
+	if newest == nil {
+		return nil, fmt.Errorf("no %s release satisfies the version constraint[%s]", channel, constraint)
+	}
+
+	return newest, nil
+}
+
+// releaseChannel returns the CHANNEL setting, which must be either
+// STABLE_CHANNEL or PRERELEASE_CHANNEL.
+func releaseChannel() string {
+	channel := strings.ToLower(viper.GetString(constants.CHANNEL))
+	if channel != constants.STABLE_CHANNEL && channel != constants.PRERELEASE_CHANNEL {
+		log.Fatalf("%s: Unsupported channel[%s] found. Valid channels are '%s' and '%s'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), channel,
+			constants.STABLE_CHANNEL, constants.PRERELEASE_CHANNEL)
+		os.Exit(1)
+	}
+
+	return channel
+}
+
+// versionConstraint returns the parsed VERSION_CONSTRAINT setting.
+func versionConstraint() distribution.Constraint {
+	constraint, err := distribution.ParseConstraint(viper.GetString(constants.VERSION_CONSTRAINT))
+	if err != nil {
+		log.Fatalf("%s: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	return constraint
+}
+
+// satisfiesConstraint reports if tag satisfies the VERSION_CONSTRAINT
+// setting. Tags that cannot be parsed never do.
+func satisfiesConstraint(provider distribution.Provider, tag string) bool {
+	version, err := provider.ParseVersion(tag)
+	if err != nil {
+		return false
+	}
+
+	return versionConstraint().Allows(version)
+}
+
+// isUpToDate reports if release is not newer than installedVersion. Tags
+// that the distribution cannot parse fall back to a plain comparison, where
+// any difference counts as an update.
+func isUpToDate(provider distribution.Provider, installedVersion string, release *distribution.Release) bool {
+	if installedVersion == constants.EMPTY {
+		return false
+	}
+
+	if strings.Compare(installedVersion, release.TagName) == 0 {
+		return true
+	}
+
+	installed, err := provider.ParseVersion(installedVersion)
+	if err != nil {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Unable to parse installed version: %s", err.Error())
+		}
+		return false
+	}
+
+	latest, err := provider.ParseVersion(release.TagName)
+	if err != nil {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Unable to parse release version: %s", err.Error())
+		}
+		return false
+	}
+
+	// A rebuild of the installed version, for example Cromite publishing the
+	// same Chromium version from a newer commit, is an update.
+	if latest.IsRebuild(installed) {
+		return false
+	}
+
+	return latest.Compare(installed) <= 0
+}
+
+// checkInterval returns the parsed CHECK_INTERVAL setting.
+func checkInterval() time.Duration {
+	interval, err := time.ParseDuration(viper.GetString(constants.CHECK_INTERVAL))
+	if err != nil {
+		log.Fatalf("%s: Invalid %s[%s], use a duration such as '6h' or '30m'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), constants.CHECK_INTERVAL, viper.GetString(constants.CHECK_INTERVAL))
+		os.Exit(1)
+	}
+
+	return interval
+}
+
+// recentlyChecked reports if the last successful update check happened less
+// than CHECK_INTERVAL ago.
+func recentlyChecked() bool {
+	interval := checkInterval()
+	if interval <= 0 {
+		return false
+	}
+
+	lastChecked, err := time.Parse(time.RFC3339, loadState().LastChecked)
+	if err != nil {
+		return false
+	}
+
+	// A last check in the future means the clock was changed, so check again.
+	since := time.Since(lastChecked)
+	return since >= 0 && since < interval
+}
+
+// recordLastCheck records the time of a successful update check.
+func recordLastCheck() {
+	saveState(func(state *launcherState) {
+		state.LastChecked = time.Now().Format(time.RFC3339)
+	})
+}
+
+// currentProvider returns the distribution.Provider selected by the
+// CHROME_DISTRIBUTION configuration setting.
+func currentProvider() distribution.Provider {
+	name := viper.GetString(constants.CHROME_DISTRIBUTION)
+	provider, found := distribution.Lookup(name)
+	if !found {
+		log.Fatalf("%s: Unsupported distribution[%s] found. Valid distributions are '%s'.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), name,
+			strings.Join(distribution.Names(), "', '"))
+		os.Exit(1)
+	}
+
+	return provider
+}
+
+// check checks the returned error of a function.
+func check(f func() error) {
+	if err := f(); err != nil {
+		fmt.Fprintf(os.Stderr, "received error: %v\n", err)
+	}
+}
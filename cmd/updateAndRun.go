@@ -1,53 +1,117 @@
-/*
-Copyright © 2018-2025 Jeff Lanzarotta
-All rights reserved.
-
-Redistribution and use in source and binary forms, with or without
-modification, are permitted provided that the following conditions are met:
-
- 1. Redistributions of source code must retain the above copyright notice,
-    this list of conditions and the following disclaimer.
-
- 2. Redistributions in binary form must reproduce the above copyright notice,
-    this list of conditions and the following disclaimer in the documentation
-    and/or other materials provided with the distribution.
-
- 3. Neither the name of the copyright holder nor the names of its contributors
-    may be used to endorse or promote products derived from this software
-    without specific prior written permission.
-
-THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
-AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
-IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
-ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
-LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
-CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
-SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
-INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
-CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
-ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
-POSSIBILITY OF SUCH DAMAGE.
-*/
-
-package cmd
-
-import (
-	"github.com/spf13/cobra"
-)
-
-// versionCmd represents the version command
-var updateAndRunCmd = &cobra.Command{
-	Use: "updateandrun",
-	Run: func(cmd *cobra.Command, args []string) {
-		updateAndRun(cmd, args)
-	},
-}
-
-func init() {
-	rootCmd.AddCommand(updateAndRunCmd)
-}
-
-func updateAndRun(command *cobra.Command, args []string) {
-	update(command, args)
-	run(command, args)
-}
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"log"
+
+	"unchrome_launcher/constants"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// versionCmd represents the version command
+var updateAndRunCmd = &cobra.Command{
+	Use: "updateandrun",
+	Run: func(cmd *cobra.Command, args []string) {
+		updateAndRun(cmd, args)
+	},
+}
+
+var checkNow bool
+
+func init() {
+	rootCmd.AddCommand(updateAndRunCmd)
+
+	updateAndRunCmd.Flags().BoolVar(&checkNow, "check-now", false, "check for updates even if the last check is more recent than check_interval")
+
+	updateAndRunCmd.Flags().Bool(constants.OFFLINE, false, "skip the update check and run the installed browser")
+	viper.BindPFlag(constants.OFFLINE, updateAndRunCmd.Flags().Lookup(constants.OFFLINE))
+}
+
+func updateAndRun(command *cobra.Command, args []string) {
+	updateBeforeRun()
+
+	run(command, args)
+}
+
+// updateBeforeRun applies a pending update and checks for a new one. When
+// several links are opened at once, only the launcher that gets the update
+// lock does so, and the others go straight to running the browser.
+func updateBeforeRun() {
+	lock, err := lockUpdates()
+	if errors.Is(err, errLockHeld) {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Skipping the update: %s", err.Error())
+		}
+		return
+	} else if err != nil {
+		log.Printf("%s: Skipping the update: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), err.Error())
+		return
+	}
+	defer lock.Release()
+
+	backgroundUpdate := viper.GetBool(constants.BACKGROUND_UPDATE)
+
+	// Switch to an update staged in the background, or deferred because the
+	// browser was running, by an earlier launch.
+	applyPendingUpdate()
+
+	// A failed update must never keep the installed browser from starting, so
+	// it is only reported. run() still fails if nothing is installed at all.
+	if viper.GetBool(constants.OFFLINE) {
+		if viper.GetBool(constants.DEBUG) {
+			log.Println("Offline, skipping the update check.")
+		}
+	} else if !checkNow && recentlyChecked() {
+		if viper.GetBool(constants.DEBUG) {
+			log.Printf("Last update check was at [%s], skipping the update check.", loadState().LastChecked)
+		}
+	} else if backgroundUpdate {
+		// The background update takes the lock itself.
+		lock.Release()
+
+		if err := startBackgroundUpdate(); err != nil {
+			log.Printf("%s: Could not start the background update: %s\n",
+				color.YellowString(constants.WARNING_NORMAL_CASE), err.Error())
+		}
+	} else if err := updateDistribution(); err != nil {
+		log.Printf("%s: Update failed, running the installed version instead: %s\n",
+			color.YellowString(constants.WARNING_NORMAL_CASE), err.Error())
+	} else {
+		recordLastCheck()
+	}
+}
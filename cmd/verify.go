@@ -0,0 +1,105 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var verifyQuick bool
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: constants.VERIFY_SHORT_DESCRIPTION,
+	Long:  constants.VERIFY_LONG_DESCRIPTION,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(verifyInstall(cmd, args))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().BoolVar(&verifyQuick, "quick", false, "only compare file sizes, without hashing every file")
+}
+
+// verifyInstall reports how the install in the bin directory differs from
+// its manifest and returns the exit code describing the result.
+func verifyInstall(_ *cobra.Command, _ []string) int {
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+
+	manifest, report, err := inspectInstall(binPath, !verifyQuick)
+	if os.IsNotExist(err) {
+		log.Printf("%s: No manifest found in [%s]. It is written by the next update or install.\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), binPath)
+		return 1
+	} else if err != nil {
+		log.Printf("%s: %s\n", color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		return 1
+	}
+
+	log.Println("     Distribution:", manifest.Distribution)
+	log.Println("Installed Version:", manifest.Tag)
+	log.Println("            Files:", len(manifest.Files))
+
+	for _, path := range report.Missing {
+		log.Printf("  %s %s\n", color.RedString("missing "), path)
+	}
+
+	for _, path := range report.Modified {
+		log.Printf("  %s %s\n", color.RedString("modified"), path)
+	}
+
+	// Files the user added are kept across updates, so they are only listed.
+	for _, path := range report.Extra {
+		log.Printf("  %s %s\n", color.YellowString("extra   "), path)
+	}
+
+	if report.IsDamaged() {
+		log.Println(color.RedString("The install is damaged, use 'repair' to fix it."))
+		return constants.EXIT_CODE_INSTALL_DAMAGED
+	}
+
+	log.Println("The install is intact.")
+	return 0
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package cmd
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// versionsCmd represents the versions command
+var versionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: constants.VERSIONS_SHORT_DESCRIPTION,
+	Long:  constants.VERSIONS_LONG_DESCRIPTION,
+	Run: func(cmd *cobra.Command, args []string) {
+		listVersions(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionsCmd)
+}
+
+func listVersions(_ *cobra.Command, _ []string) {
+	binPath := filepath.Join(globals.ExeDir, viper.GetString(constants.BIN_DIRECTORY))
+
+	versions, err := keptVersions(binPath)
+	if err != nil {
+		log.Fatalf("%s: Could not list kept versions: %s\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err.Error())
+		os.Exit(1)
+	}
+
+	state := loadState()
+	installed := "installed"
+	if state.InstalledChannel != constants.EMPTY {
+		installed += ", " + state.InstalledChannel
+	}
+	log.Printf("* %s (%s)\n", color.YellowString(state.InstalledRelease), installed)
+
+	if len(versions) == 0 {
+		log.Printf("No previous versions are kept in [%s].\n", versionsDirectory(binPath))
+		return
+	}
+
+	for _, version := range versions {
+		log.Printf("  %s (replaced %s)\n", version.Tag, version.Time.Format("2006-01-02 15:04"))
+	}
+}
@@ -1,36 +1,94 @@
-package constants
-
-const APPLICATION_NAME = "Unchrome Launcher"
-const APPLICATION_NAME_LOWERCASE = "unchrome_launcher"
-const BIN_DIRECTORY = "bin_directory"
-const CHROME_APPLICATION_NAME = "chrome.exe"
-const CHROME_COMMAND_LINE_OPTIONS string = "chrome_command_line_options"
-const CHROME_DISTRIBUTION = "chrome_distribution"
-const CROMITE_ASSET_NAME string = "chrome-win.zip"
-const CROMITE_DISTRIBUTION = "cromite"
-const CROMITE_GITHUB_URL string = "https://api.github.com/repos/uazo/cromite/releases/latest"
-const DEBUG = "debug"
-const DEFAULT_COMMAND = "updateandrun"
-const DOWNLOAD_DIRECTORY = "download_directory"
-const EMPTY string = ""
-const FATAL_NORMAL_CASE string = "Fatal"
-const HELP_SHORT_DESCRIPTION = "Show help for command"
-const INFO_NORMAL_CASE string = "Info"
-const INSTALLED_VERSION string = "installed_release"
-const PAUSE_AFTER_RUN string = "pause_after_run"
-const PAUSE_ON_UPDATE string = "pause_on_update"
-const PROFILE_DIRECTORY = "profile_directory"
-const ROOT_LONG_DESCRIPTION = "Unchrome Launcher is a simple tool used to update and run your 'Unchrome' Chromium instance."
-const ROOT_SHORT_DESCRIPTION = "Simple program used to update and run your Unchrome Chromium instance."
-const SPACE = " "
-const UNGOOGLED_CHROMIUM_DISTRIBUTION = "ungoogled"
-const UNGOOGLED_CHROMIUM_WINDOWS_ASSET_NAME string = "_windows_x64.zip"
-const UNGOOGLED_CHROMIUM_WINDOWS_GITHUB_URL string = "https://api.github.com/repos/ungoogled-software/ungoogled-chromium-windows/releases/latest"
-const UNGOOGLED_WINCHROME_ASSET_NAME string = "_Win64.7z"
-const UNGOOGLED_WINCHROME_DISTRIBUTION = "ungoogled-chromium"
-const UNGOOGLED_WINCHROME_GITHUB_URL string = "https://api.github.com/repos/macchrome/winchrome/releases/latest"
-const VERSION_LONG_DESCRIPTION = "Show the version information."
-const VERSION_SHORT_DESCRIPTION = "Show the version information"
-const WEB_LONG_DESCRIPTION = "Open the Unchrome Updater website in your default browser."
-const WEB_SHORT_DESCRIPTION = "Open the Unchrome Updater website in your default browser"
-const WEB_SITE string = "https://github.com/jlanzarotta/unchrome_launcher/"
+package constants
+
+const ALLOW_DOWNGRADE string = "allow_downgrade"
+const APPLICATION_NAME = "Unchrome Launcher"
+const APPLICATION_NAME_LOWERCASE = "unchrome_launcher"
+const ARCHIVE_MAX_ENTRIES string = "archive_max_entries"
+const ARCHIVE_MAX_FILE_SIZE_MB string = "archive_max_file_size_mb"
+const ARCHIVE_MAX_RATIO string = "archive_max_ratio"
+const ARCHIVE_MAX_SIZE_MB string = "archive_max_size_mb"
+const ARCHIVE_STRIP_COMPONENTS string = "archive_strip_components"
+const AUTO_STRIP_COMPONENTS string = "auto"
+const BACKGROUND_UPDATE string = "background_update"
+const BIN_DIRECTORY = "bin_directory"
+const CHANNEL string = "channel"
+const CHECK_INTERVAL string = "check_interval"
+const CHECK_LONG_DESCRIPTION = "Check if a newer release is available without installing it. Exits with 0 when up to date, 10 when an update is available, and another non-zero code on errors."
+const CHECK_SHORT_DESCRIPTION = "Check if a newer release is available"
+const CHROME_APPLICATION_NAME = "chrome.exe"
+const CHROME_COMMAND_LINE_OPTIONS string = "chrome_command_line_options"
+const CHROME_DISTRIBUTION = "chrome_distribution"
+const CHROME_LINUX_APPLICATION_NAME = "chrome"
+const CROMITE_ASSET_NAME string = "chrome-win.zip"
+const CROMITE_DISTRIBUTION = "cromite"
+const CROMITE_GITHUB_REPOSITORY string = "uazo/cromite"
+const CROMITE_LINUX_ASSET_NAME string = "chrome-lin64.tar.gz"
+const DEBUG = "debug"
+const DEFAULT_COMMAND = "updateandrun"
+const DOWNLOAD_DIRECTORY = "download_directory"
+const EMPTY string = ""
+const EXIT_CODE_ASSET_NOT_FOUND = 3
+const EXIT_CODE_INSTALL_DAMAGED = 11
+const EXIT_CODE_RELEASE_LOOKUP_FAILED = 2
+const EXIT_CODE_UP_TO_DATE = 0
+const EXIT_CODE_UPDATE_AVAILABLE = 10
+const FATAL_NORMAL_CASE string = "Fatal"
+const GITHUB_API_URL string = "https://api.github.com"
+const GITHUB_CACHE_DIRECTORY string = "cache"
+const GITHUB_MAX_RELEASE_PAGES = 10
+const GITHUB_TOKEN string = "github_token"
+const HELP_SHORT_DESCRIPTION = "Show help for command"
+const HTTP_CONNECT_TIMEOUT string = "http_connect_timeout"
+const HTTP_READ_TIMEOUT string = "http_read_timeout"
+const HTTP_RETRIES string = "http_retries"
+const INFO_NORMAL_CASE string = "Info"
+const INSTALL_LONG_DESCRIPTION = "Install a specific release with --tag, or a local archive with --from. The release is installed whether it is newer or older than the installed version."
+const INSTALL_SHORT_DESCRIPTION = "Install a specific release or a local archive"
+const INSTALLED_CHANNEL string = "installed_channel"
+const INSTALLED_VERSION string = "installed_release"
+const KEEP_VERSIONS string = "keep_versions"
+const LAST_CHECKED string = "last_checked"
+const LENIENT string = "lenient"
+const MANIFEST_FILE_NAME string = ".unchrome_launcher.manifest.json"
+const OFFLINE string = "offline"
+const PAUSE_AFTER_RUN string = "pause_after_run"
+const PAUSE_ON_UPDATE string = "pause_on_update"
+const PENDING_CHANNEL string = "pending_channel"
+const PENDING_RELEASE string = "pending_release"
+const PRERELEASE_CHANNEL string = "prerelease"
+const PROFILE_DIRECTORY = "profile_directory"
+const REPAIR_LONG_DESCRIPTION = "Re-extract the installed release when files from its archive are missing or modified. The cached archive is used when its checksum still matches, otherwise the release is downloaded again. Files you added to the bin directory are kept."
+const REPAIR_SHORT_DESCRIPTION = "Repair a damaged install"
+const REQUIRE_CHECKSUM string = "require_checksum"
+const ROLLBACK_LONG_DESCRIPTION = "Switch the active install back to a previously installed version kept next to the bin directory. Without a tag, the most recently replaced version is used. Updates are put on hold until 'update --force' or 'install' is run."
+const ROLLBACK_SHORT_DESCRIPTION = "Switch back to a previously installed version"
+const ROOT_LONG_DESCRIPTION = "Unchrome Launcher is a simple tool used to update and run your 'Unchrome' Chromium instance."
+const ROOT_SHORT_DESCRIPTION = "Simple program used to update and run your Unchrome Chromium instance."
+const RUNNING_BROWSER_ABORT string = "abort"
+const RUNNING_BROWSER_ACTION string = "running_browser_action"
+const RUNNING_BROWSER_DEFER string = "defer"
+const RUNNING_BROWSER_TIMEOUT string = "running_browser_timeout"
+const RUNNING_BROWSER_WAIT string = "wait"
+const SPACE = " "
+const STABLE_CHANNEL string = "stable"
+const UNGOOGLED_CHROMIUM_DISTRIBUTION = "ungoogled"
+const UNGOOGLED_CHROMIUM_LINUX_ASSET_NAME string = "_linux.tar.xz"
+const UNGOOGLED_CHROMIUM_LINUX_GITHUB_REPOSITORY string = "ungoogled-software/ungoogled-chromium-portablelinux"
+const UNGOOGLED_CHROMIUM_WINDOWS_ASSET_NAME string = "_windows_x64.zip"
+const UNGOOGLED_CHROMIUM_WINDOWS_GITHUB_REPOSITORY string = "ungoogled-software/ungoogled-chromium-windows"
+const UNGOOGLED_WINCHROME_ASSET_NAME string = "_Win64.7z"
+const UNGOOGLED_WINCHROME_DISTRIBUTION = "ungoogled-chromium"
+const UNGOOGLED_WINCHROME_GITHUB_REPOSITORY string = "macchrome/winchrome"
+const UPDATE_LOCK_FILE_NAME string = ".unchrome_launcher.lock"
+const UPDATE_LOCK_TIMEOUT string = "update_lock_timeout"
+const VERIFY_LONG_DESCRIPTION = "Compare the bin directory with the manifest written when the release was installed, and report missing, modified or extra files. Exits with 0 when the install is intact, 11 when it is damaged, and another non-zero code on errors."
+const VERIFY_SHORT_DESCRIPTION = "Verify the installed files against the install manifest"
+const VERSION_CONSTRAINT string = "version_constraint"
+const VERSION_LONG_DESCRIPTION = "Show the version information."
+const VERSION_SHORT_DESCRIPTION = "Show the version information"
+const VERSIONS_LONG_DESCRIPTION = "List the previously installed versions that are kept locally and can be used with rollback."
+const VERSIONS_SHORT_DESCRIPTION = "List the locally kept versions"
+const WARNING_NORMAL_CASE string = "Warning"
+const WEB_LONG_DESCRIPTION = "Open the Unchrome Updater website in your default browser."
+const WEB_SHORT_DESCRIPTION = "Open the Unchrome Updater website in your default browser"
+const WEB_SITE string = "https://github.com/jlanzarotta/unchrome_launcher/"
@@ -0,0 +1,104 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// CacheDirectory is where GitHub API responses are cached. Caching is
+// disabled while it is empty.
+var CacheDirectory string
+
+// cachedResponse is a GitHub API response body stored with the ETag needed
+// to revalidate it.
+type cachedResponse struct {
+	URL  string          `json:"url"`
+	ETag string          `json:"etag"`
+	Next string          `json:"next"`
+	Body json.RawMessage `json:"body"`
+}
+
+// cachePath returns the file the response for url is cached in.
+func cachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(CacheDirectory, hex.EncodeToString(sum[:8])+".json")
+}
+
+// readCachedResponse returns the cached response for url, or nil if there is
+// none. A cache that cannot be read is treated as empty.
+func readCachedResponse(url string) *cachedResponse {
+	if CacheDirectory == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(cachePath(url))
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(content, &cached); err != nil || cached.URL != url {
+		return nil
+	}
+
+	return &cached
+}
+
+// writeCachedResponse stores the response for url. Failing to do so only
+// costs a full request next time, so errors are ignored.
+func writeCachedResponse(url string, cached *cachedResponse) {
+	if CacheDirectory == "" || cached.ETag == "" {
+		return
+	}
+
+	cached.URL = url
+	content, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(CacheDirectory, 0755); err != nil {
+		return
+	}
+
+	temporaryPath := cachePath(url) + ".tmp"
+	if err := os.WriteFile(temporaryPath, content, 0644); err != nil {
+		return
+	}
+
+	os.Rename(temporaryPath, cachePath(url))
+}
@@ -0,0 +1,186 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"unchrome_launcher/httpclient"
+)
+
+// sha256Pattern matches a hex encoded SHA-256 digest.
+var sha256Pattern = regexp.MustCompile(`(?i)\b[0-9a-f]{64}\b`)
+
+// checksumFileNames are the names of sidecar assets that list the digests of
+// several assets, one per line.
+var checksumFileNames = []string{
+	"checksums.txt",
+	"sha256.txt",
+	"sha256sum.txt",
+	"sha256sums",
+	"sha256sums.txt",
+}
+
+// ExpectedDigest looks for the published SHA-256 digest of asset. The digest
+// reported by the GitHub API is used first, followed by sidecar checksum
+// assets and finally the release notes. An empty string is returned when no
+// digest was published.
+func (g gitHub) ExpectedDigest(release *Release, asset *Asset) (string, error) {
+	if strings.HasPrefix(asset.Digest, "sha256:") {
+		return strings.ToLower(strings.TrimPrefix(asset.Digest, "sha256:")), nil
+	}
+
+	for _, sidecar := range release.Assets {
+		name := strings.ToLower(sidecar.Name)
+
+		if name == strings.ToLower(asset.Name)+".sha256" || name == strings.ToLower(asset.Name)+".sha256sum" {
+			content, err := fetchText(sidecar.BrowserDownloadURL)
+			if err != nil {
+				return "", err
+			}
+
+			if digest := sha256Pattern.FindString(content); digest != "" {
+				return strings.ToLower(digest), nil
+			}
+		}
+	}
+
+	for _, sidecar := range release.Assets {
+		for _, checksumFileName := range checksumFileNames {
+			if strings.ToLower(sidecar.Name) != checksumFileName {
+				continue
+			}
+
+			content, err := fetchText(sidecar.BrowserDownloadURL)
+			if err != nil {
+				return "", err
+			}
+
+			if digest := findDigest(content, asset.Name); digest != "" {
+				return digest, nil
+			}
+		}
+	}
+
+	return findDigest(release.Body, asset.Name), nil
+}
+
+// findDigest scans text for the SHA-256 digest belonging to name. A digest
+// counts when it is on the same line as name, or on a following line before
+// another asset is mentioned, which is how ungoogled-chromium-windows lists
+// its hashes in the release notes.
+func findDigest(text string, name string) string {
+	inSection := false
+
+	for _, line := range strings.Split(text, "\n") {
+		mentionsName := mentionsFile(line, name)
+		digest := sha256Pattern.FindString(line)
+
+		if digest != "" && (mentionsName || inSection) {
+			return strings.ToLower(digest)
+		}
+
+		if mentionsName {
+			inSection = true
+		} else if digest == "" && looksLikeFileName(line) {
+			inSection = false
+		}
+	}
+
+	return ""
+}
+
+// mentionsFile reports if line names the file name on its own, rather than
+// as part of a longer name such as its "name.sig" signature.
+func mentionsFile(line string, name string) bool {
+	for offset := 0; ; {
+		index := strings.Index(line[offset:], name)
+		if index < 0 {
+			return false
+		}
+
+		start := offset + index
+		end := start + len(name)
+
+		before := start == 0 || !isFileNameByte(line[start-1])
+		after := end == len(line) || !isFileNameByte(line[end]) ||
+			(line[end] == '.' && (end+1 == len(line) || !isFileNameByte(line[end+1])))
+		if before && after {
+			return true
+		}
+
+		offset = start + 1
+	}
+}
+
+// isFileNameByte reports if c can be part of an asset name.
+func isFileNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_'
+}
+
+// looksLikeFileName reports if line names another downloadable file, which
+// ends the section that belongs to the previous one.
+func looksLikeFileName(line string) bool {
+	line = strings.ToLower(strings.TrimSpace(line))
+
+	for _, extension := range []string{".zip", ".7z", ".exe", ".msi", ".tar.xz", ".tar.gz", ".appimage", ".apk"} {
+		if strings.Contains(line, extension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchText downloads a small text file such as a checksum sidecar.
+func fetchText(url string) (string, error) {
+	resp, err := httpclient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("checksum download failed: %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
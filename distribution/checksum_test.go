@@ -0,0 +1,198 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Digests of made up content, so that every asset has its own.
+func md5Of(name string) string    { return fmt.Sprintf("%x", md5.Sum([]byte(name))) }
+func sha1Of(name string) string   { return fmt.Sprintf("%x", sha1.Sum([]byte(name))) }
+func sha256Of(name string) string { return fmt.Sprintf("%x", sha256.Sum256([]byte(name))) }
+
+const (
+	ungoogledInstaller = "ungoogled-chromium_139.0.7258.154-1.1_installer_x64.exe"
+	ungoogledX64       = "ungoogled-chromium_139.0.7258.154-1.1_windows_x64.zip"
+	ungoogledX86       = "ungoogled-chromium_139.0.7258.154-1.1_windows_x86.zip"
+	ungoogledArm64     = "ungoogled-chromium_139.0.7258.154-1.1_windows_arm64.zip"
+)
+
+// ungoogledReleaseBody lists the hashes of every asset below its name, the
+// way ungoogled-chromium-windows writes its release notes.
+func ungoogledReleaseBody() string {
+	var body strings.Builder
+	body.WriteString("Changes since the last release:\r\n\r\n* Update to Chromium 139.0.7258.154\r\n\r\n## Hashes\r\n")
+	for _, name := range []string{ungoogledInstaller, ungoogledX64, ungoogledX86, ungoogledArm64} {
+		fmt.Fprintf(&body, "\r\n### %s\r\n", name)
+		fmt.Fprintf(&body, "md5: `%s`\r\n", md5Of(name))
+		fmt.Fprintf(&body, "sha1: `%s`\r\n", sha1Of(name))
+		fmt.Fprintf(&body, "sha256: `%s`\r\n", sha256Of(name))
+	}
+	return body.String()
+}
+
+// cromiteChecksums is a sha256sum listing of the assets of a Cromite
+// release.
+func cromiteChecksums() string {
+	var sums strings.Builder
+	for _, name := range []string{"arm64_ChromePublic.apk", "arm64_SystemWebView.apk", "chrome-lin64.tar.gz", "chrome-win.zip", "x64_ChromePublic.apk"} {
+		fmt.Fprintf(&sums, "%s  %s\n", sha256Of(name), name)
+	}
+	return sums.String()
+}
+
+func TestFindDigest(t *testing.T) {
+	tests := []struct {
+		description string
+		text        string
+		name        string
+		want        string
+	}{
+		{description: "ungoogled zip", text: ungoogledReleaseBody(), name: ungoogledX64, want: sha256Of(ungoogledX64)},
+		{description: "ungoogled neighbour zip", text: ungoogledReleaseBody(), name: ungoogledX86, want: sha256Of(ungoogledX86)},
+		{description: "ungoogled installer", text: ungoogledReleaseBody(), name: ungoogledInstaller, want: sha256Of(ungoogledInstaller)},
+		{description: "ungoogled last section", text: ungoogledReleaseBody(), name: ungoogledArm64, want: sha256Of(ungoogledArm64)},
+		{description: "ungoogled missing asset", text: ungoogledReleaseBody(), name: "ungoogled-chromium_139.0.7258.154-1.1_linux.tar.xz", want: ""},
+		{
+			description: "ungoogled only md5 and sha1",
+			text:        fmt.Sprintf("### %s\nmd5: %s\nsha1: %s\n\n### %s\nsha256: %s\n", ungoogledX64, md5Of(ungoogledX64), sha1Of(ungoogledX64), ungoogledX86, sha256Of(ungoogledX86)),
+			name:        ungoogledX64,
+			want:        "",
+		},
+		{description: "cromite sums", text: cromiteChecksums(), name: "chrome-win.zip", want: sha256Of("chrome-win.zip")},
+		{description: "cromite linux sums", text: cromiteChecksums(), name: "chrome-lin64.tar.gz", want: sha256Of("chrome-lin64.tar.gz")},
+		{description: "cromite binary mode", text: fmt.Sprintf("%s *chrome-lin64.tar.gz\n%s *chrome-win.zip\n", sha256Of("chrome-lin64.tar.gz"), sha256Of("chrome-win.zip")), name: "chrome-win.zip", want: sha256Of("chrome-win.zip")},
+		{
+			description: "cromite signature listed first",
+			text:        fmt.Sprintf("%s  chrome-win.zip.sig\n%s  chrome-win.zip\n", sha256Of("chrome-win.zip.sig"), sha256Of("chrome-win.zip")),
+			name:        "chrome-win.zip",
+			want:        sha256Of("chrome-win.zip"),
+		},
+		{
+			description: "longer name listed first",
+			text:        fmt.Sprintf("%s  old-chrome-win.zip\n%s  chrome-win.zip\n", sha256Of("old-chrome-win.zip"), sha256Of("chrome-win.zip")),
+			name:        "chrome-win.zip",
+			want:        sha256Of("chrome-win.zip"),
+		},
+		{description: "end of sentence", text: fmt.Sprintf("Download chrome-win.zip.\nsha256: %s\n", sha256Of("chrome-win.zip")), name: "chrome-win.zip", want: sha256Of("chrome-win.zip")},
+		{description: "uppercase", text: fmt.Sprintf("%s  chrome-win.zip\n", strings.ToUpper(sha256Of("chrome-win.zip"))), name: "chrome-win.zip", want: sha256Of("chrome-win.zip")},
+		{description: "empty", text: "", name: "chrome-win.zip", want: ""},
+	}
+
+	for _, test := range tests {
+		if got := findDigest(test.text, test.name); got != test.want {
+			t.Errorf("%s: findDigest(%q) = %q, want %q", test.description, test.name, got, test.want)
+		}
+	}
+}
+
+func TestExpectedDigest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/SHA256SUMS":
+			fmt.Fprint(w, cromiteChecksums())
+		case "/chrome-win.zip.sha256":
+			fmt.Fprintf(w, "%s\n", sha256Of("chrome-win.zip sidecar"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	asset := func(name string) Asset {
+		return Asset{Name: name, BrowserDownloadURL: server.URL + "/" + name}
+	}
+
+	tests := []struct {
+		description string
+		release     Release
+		asset       Asset
+		want        string
+	}{
+		{
+			description: "api digest",
+			release:     Release{Body: ungoogledReleaseBody()},
+			asset:       Asset{Name: ungoogledX64, Digest: "sha256:" + strings.ToUpper(sha256Of("api"))},
+			want:        sha256Of("api"),
+		},
+		{
+			description: "release notes",
+			release:     Release{Body: ungoogledReleaseBody(), Assets: []Asset{asset(ungoogledInstaller), asset(ungoogledX64), asset(ungoogledX86)}},
+			asset:       asset(ungoogledX64),
+			want:        sha256Of(ungoogledX64),
+		},
+		{
+			description: "checksum listing",
+			release:     Release{Assets: []Asset{asset("arm64_ChromePublic.apk"), asset("chrome-win.zip"), asset("SHA256SUMS")}},
+			asset:       asset("chrome-win.zip"),
+			want:        sha256Of("chrome-win.zip"),
+		},
+		{
+			description: "asset sidecar before listing",
+			release:     Release{Assets: []Asset{asset("chrome-win.zip"), asset("SHA256SUMS"), asset("chrome-win.zip.sha256")}},
+			asset:       asset("chrome-win.zip"),
+			want:        sha256Of("chrome-win.zip sidecar"),
+		},
+		{
+			description: "neighbour sidecar",
+			release:     Release{Body: cromiteChecksums(), Assets: []Asset{asset("chrome-lin64.tar.gz"), asset("chrome-win.zip"), asset("chrome-win.zip.sha256")}},
+			asset:       asset("chrome-lin64.tar.gz"),
+			want:        sha256Of("chrome-lin64.tar.gz"),
+		},
+		{
+			description: "unpublished",
+			release:     Release{Body: "Changes since the last release", Assets: []Asset{asset("chrome-win.zip")}},
+			asset:       asset("chrome-win.zip"),
+			want:        "",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := gitHub{}.ExpectedDigest(&test.release, &test.asset)
+		if err != nil {
+			t.Errorf("%s: ExpectedDigest(%q) error = %v", test.description, test.asset.Name, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%s: ExpectedDigest(%q) = %q, want %q", test.description, test.asset.Name, got, test.want)
+		}
+	}
+}
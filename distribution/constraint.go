@@ -0,0 +1,163 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// constraintTermPattern matches a single constraint such as ">=139",
+// "!=139.0.7258.66" or "<140.0.7339.80-1.1".
+var constraintTermPattern = regexp.MustCompile(`^(==|=|!=|>=|<=|>|<)?[vV]?(\d+(?:\.\d+){0,3})(?:-(\d+(?:\.\d+)*))?$`)
+
+// Constraint restricts which release versions may be installed. It is a
+// list of terms that must all be satisfied.
+type Constraint struct {
+	text  string
+	terms []constraintTerm
+}
+
+// constraintTerm compares a version with a possibly partial version. Only
+// the Chromium version parts that are given are compared, so "139" matches
+// every 139.x.y.z release, and the revision is only compared when it is
+// given.
+type constraintTerm struct {
+	operator string
+	chromium []int
+	revision []int
+}
+
+// ParseConstraint parses a space or comma separated list of terms, such as
+// ">=139 <140 !=139.0.7258.66". An empty string allows every version.
+func ParseConstraint(text string) (Constraint, error) {
+	constraint := Constraint{text: strings.TrimSpace(text)}
+
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+
+	// Allow a space between the operator and the version, as in ">= 139".
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if strings.Trim(field, "=!<>") == "" && i+1 < len(fields) {
+			field += fields[i+1]
+			i++
+		}
+
+		match := constraintTermPattern.FindStringSubmatch(field)
+		if match == nil {
+			return Constraint{}, fmt.Errorf("invalid version constraint [%s]", field)
+		}
+
+		term := constraintTerm{operator: match[1]}
+		if term.operator == "" || term.operator == "==" {
+			term.operator = "="
+		}
+
+		chromium, err := parseDottedNumbers(match[2])
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid version constraint [%s]: %w", field, err)
+		}
+		term.chromium = chromium
+
+		if match[3] != "" {
+			revision, err := parseDottedNumbers(match[3])
+			if err != nil {
+				return Constraint{}, fmt.Errorf("invalid version constraint [%s]: %w", field, err)
+			}
+			term.revision = revision
+		}
+
+		constraint.terms = append(constraint.terms, term)
+	}
+
+	return constraint, nil
+}
+
+// IsEmpty reports if the constraint allows every version.
+func (c Constraint) IsEmpty() bool {
+	return len(c.terms) == 0
+}
+
+// String returns the constraint as it was written.
+func (c Constraint) String() string {
+	return c.text
+}
+
+// Allows reports if version satisfies every term of the constraint.
+func (c Constraint) Allows(version Version) bool {
+	for _, term := range c.terms {
+		if !term.allows(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t constraintTerm) allows(version Version) bool {
+	result := t.compare(version)
+
+	switch t.operator {
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	default:
+		return result == 0
+	}
+}
+
+// compare compares version with the term's version, looking only at the
+// parts the term specifies.
+func (t constraintTerm) compare(version Version) int {
+	for i, number := range t.chromium {
+		if version.Chromium[i] != number {
+			return compareInts(version.Chromium[i], number)
+		}
+	}
+
+	if t.revision == nil {
+		return 0
+	}
+
+	return Version{Chromium: version.Chromium, Revision: version.Revision}.Compare(
+		Version{Chromium: version.Chromium, Revision: t.revision})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import "testing"
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		text    string
+		terms   int
+		invalid bool
+	}{
+		{text: "", terms: 0},
+		{text: "   ", terms: 0},
+		{text: ">=139 <140", terms: 2},
+		{text: ">=139,<140", terms: 2},
+		{text: ">= 139 < 140", terms: 2},
+		{text: "!=139.0.7258.66", terms: 1},
+		{text: "139.0.7258.154-1.1", terms: 1},
+		{text: "==v139", terms: 1},
+		{text: "<140.0.7339.80-1.1", terms: 1},
+		{text: "~139", invalid: true},
+		{text: ">=139.0.7258.154.1", invalid: true},
+		{text: ">=latest", invalid: true},
+		{text: ">=139-", invalid: true},
+	}
+
+	for _, test := range tests {
+		constraint, err := ParseConstraint(test.text)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("ParseConstraint(%q) = %v, want an error", test.text, constraint.terms)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) error = %v", test.text, err)
+			continue
+		}
+
+		if len(constraint.terms) != test.terms {
+			t.Errorf("ParseConstraint(%q) has %d terms, want %d", test.text, len(constraint.terms), test.terms)
+		}
+
+		if constraint.IsEmpty() != (test.terms == 0) {
+			t.Errorf("ParseConstraint(%q).IsEmpty() = %v", test.text, constraint.IsEmpty())
+		}
+	}
+}
+
+func TestConstraintAllows(t *testing.T) {
+	tests := []struct {
+		constraint string
+		tag        string
+		want       bool
+	}{
+		{constraint: "", tag: "139.0.7258.154-1.1", want: true},
+		{constraint: ">=139 <140", tag: "139.0.7258.154-1.1", want: true},
+		{constraint: ">=139 <140", tag: "140.0.7339.80-1.1", want: false},
+		{constraint: ">=139 <140", tag: "138.0.7204.183-1.1", want: false},
+		{constraint: "139", tag: "139.0.7258.154-1.1", want: true},
+		{constraint: "139", tag: "140.0.7339.80-1.1", want: false},
+		{constraint: "!=139.0.7258.66", tag: "139.0.7258.66-1.1", want: false},
+		{constraint: "!=139.0.7258.66", tag: "139.0.7258.66-2.1", want: false},
+		{constraint: "!=139.0.7258.66", tag: "139.0.7258.154-1.1", want: true},
+		{constraint: "!=139.0.7258.66-1.1", tag: "139.0.7258.66-1.1", want: false},
+		{constraint: "!=139.0.7258.66-1.1", tag: "139.0.7258.66-2.1", want: true},
+		{constraint: "<=139.0.7258", tag: "139.0.7258.999-1", want: true},
+		{constraint: "<=139.0.7258", tag: "139.0.7259.1-1", want: false},
+		{constraint: ">139.0.7258.154-1.1", tag: "139.0.7258.154-1.2", want: true},
+		{constraint: ">139.0.7258.154-1.1", tag: "139.0.7258.154-1.1", want: false},
+		{constraint: ">139", tag: "139.0.7258.154-1.1", want: false},
+		{constraint: ">139", tag: "140.0.7339.80-1.1", want: true},
+	}
+
+	for _, test := range tests {
+		constraint, err := ParseConstraint(test.constraint)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		version, err := ungoogled{}.ParseVersion(test.tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := constraint.Allows(version); got != test.want {
+			t.Errorf("ParseConstraint(%q).Allows(%s) = %v, want %v", test.constraint, test.tag, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"runtime"
+	"strings"
+
+	"unchrome_launcher/archive"
+	"unchrome_launcher/constants"
+)
+
+// cromite provides the Cromite builds from the uazo/cromite project. The
+// Windows and Linux builds are assets of the same release.
+type cromite struct {
+	gitHub
+}
+
+func init() {
+	assetSuffix := constants.CROMITE_ASSET_NAME
+	if runtime.GOOS == "linux" {
+		assetSuffix = constants.CROMITE_LINUX_ASSET_NAME
+	}
+
+	Register(cromite{gitHub{
+		repository:  constants.CROMITE_GITHUB_REPOSITORY,
+		assetSuffix: assetSuffix,
+	}})
+}
+
+func (cromite) Name() string {
+	return constants.CROMITE_DISTRIBUTION
+}
+
+func (cromite) Layout() Layout {
+	return Layout{StripComponents: archive.AutoStripComponents}
+}
+
+func (cromite) Executable() string {
+	return chromeExecutable()
+}
+
+// ParseVersion parses tags such as "v139.0.7258.158-1a2b3c4d". The commit
+// hash after the dash says nothing about ordering, but a new hash for the
+// same Chromium version is a rebuild, so it is kept as the build.
+func (cromite) ParseVersion(tag string) (Version, error) {
+	version, rest, err := parseChromiumVersion(tag)
+	if err != nil {
+		return Version{}, err
+	}
+
+	version.Build = strings.TrimPrefix(rest, "-")
+
+	return version, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"unchrome_launcher/constants"
+)
+
+// Release is the subset of a GitHub release that the launcher cares about.
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Body       string  `json:"body"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a single downloadable file attached to a Release.
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	Size               int64  `json:"size"`
+	Digest             string `json:"digest"`
+}
+
+// Layout describes how the files inside a distribution's archive are laid
+// out relative to the bin directory.
+type Layout struct {
+	// StripComponents is the number of leading path elements that are
+	// removed from every archive entry during extraction, or
+	// archive.AutoStripComponents to detect it from the entries.
+	StripComponents int
+}
+
+// Provider is implemented by every supported Chromium distribution.
+type Provider interface {
+	// Name returns the value used for chrome_distribution in the
+	// configuration file.
+	Name() string
+
+	// LatestRelease resolves the newest published release.
+	LatestRelease() (*Release, error)
+
+	// ReleaseByTag resolves the release with the given tag.
+	ReleaseByTag(tag string) (*Release, error)
+
+	// Releases walks the published releases, newest first, one page at a
+	// time until visit returns false.
+	Releases(visit func(releases []Release) bool) error
+
+	// SelectAsset picks the asset to download from the given release.
+	SelectAsset(release *Release) (*Asset, error)
+
+	// ExpectedDigest returns the published, hex encoded SHA-256 digest of
+	// asset, or an empty string if the distribution did not publish one.
+	ExpectedDigest(release *Release, asset *Asset) (string, error)
+
+	// ParseVersion parses one of the distribution's release tags so that it
+	// can be compared with another.
+	ParseVersion(tag string) (Version, error)
+
+	// Layout describes the structure of the downloaded archive. It can be
+	// overridden with archive_strip_components in the configuration file.
+	Layout() Layout
+
+	// Executable returns the name of the browser executable, relative to the
+	// bin directory.
+	Executable() string
+}
+
+var providers = map[string]Provider{}
+
+// Register makes a Provider available by its name. It is intended to be
+// called from the init function of the file implementing the Provider.
+func Register(provider Provider) {
+	name := strings.ToLower(provider.Name())
+	if _, found := providers[name]; found {
+		panic(fmt.Sprintf("distribution: Register called twice for %s", name))
+	}
+
+	providers[name] = provider
+}
+
+// Lookup returns the Provider registered under name. The comparison is case
+// insensitive.
+func Lookup(name string) (Provider, bool) {
+	provider, found := providers[strings.ToLower(name)]
+	return provider, found
+}
+
+// Names returns the names of all registered providers in sorted order.
+func Names() []string {
+	var names []string
+	for _, provider := range providers {
+		names = append(names, provider.Name())
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// chromeExecutable returns the name of the Chromium executable on the
+// operating system we are running on.
+func chromeExecutable() string {
+	if runtime.GOOS == "linux" {
+		return constants.CHROME_LINUX_APPLICATION_NAME
+	}
+
+	return constants.CHROME_APPLICATION_NAME
+}
@@ -0,0 +1,239 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"unchrome_launcher/constants"
+	"unchrome_launcher/httpclient"
+)
+
+// GitHubToken, when set, is used to authenticate GitHub API requests, which
+// raises the rate limit from 60 to 5000 requests an hour.
+var GitHubToken string
+
+// gitHub implements the release lookups shared by every distribution that
+// publishes its builds as GitHub releases.
+type gitHub struct {
+	repository  string
+	assetSuffix string
+}
+
+// LatestRelease fetches the release marked as latest in the repository.
+func (g gitHub) LatestRelease() (*Release, error) {
+	var release Release
+	if err := getJSON(fmt.Sprintf("%s/repos/%s/releases/latest", constants.GITHUB_API_URL, g.repository), &release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// ReleaseByTag fetches the release with the given tag.
+func (g gitHub) ReleaseByTag(tag string) (*Release, error) {
+	var release Release
+	if err := getJSON(fmt.Sprintf("%s/repos/%s/releases/tags/%s", constants.GITHUB_API_URL, g.repository, url.PathEscape(tag)), &release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
+// Releases walks the releases of the repository, newest first, calling
+// visit with one page at a time until it returns false or there are no more
+// pages.
+func (g gitHub) Releases(visit func(releases []Release) bool) error {
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=100", constants.GITHUB_API_URL, g.repository)
+
+	for page := 0; url != constants.EMPTY && page < constants.GITHUB_MAX_RELEASE_PAGES; page++ {
+		var releases []Release
+		next, err := getJSONPage(url, &releases)
+		if err != nil {
+			return err
+		}
+
+		if !visit(releases) {
+			break
+		}
+
+		url = next
+	}
+
+	return nil
+}
+
+// SelectAsset returns the first asset whose name ends with the distribution's
+// asset suffix.
+func (g gitHub) SelectAsset(release *Release) (*Asset, error) {
+	for i := range release.Assets {
+		if strings.HasSuffix(release.Assets[i].Name, g.assetSuffix) {
+			return &release.Assets[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("asset ending with [%s] not found in release [%s]", g.assetSuffix, release.TagName)
+}
+
+// getJSON requests url from the GitHub API and decodes the response into v.
+func getJSON(url string, v any) error {
+	_, err := getJSONPage(url, v)
+	return err
+}
+
+// getJSONPage requests url from the GitHub API, decodes the response into v
+// and returns the URL of the next page, if there is one.
+//
+// Responses are cached in CacheDirectory together with their ETag, so that
+// repeated lookups send If-None-Match and a 304 Not Modified reuses the
+// cached body.
+func getJSONPage(url string, v any) (string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if GitHubToken != "" {
+		req.Header.Set("Authorization", "Bearer "+GitHubToken)
+	}
+
+	cached := readCachedResponse(url)
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := checkRateLimit(resp); err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		if err := json.Unmarshal(cached.Body, v); err != nil {
+			return "", err
+		}
+
+		return cached.Next, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API request failed: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return "", err
+	}
+
+	next := nextPageURL(resp.Header.Get("Link"))
+	writeCachedResponse(url, &cachedResponse{
+		ETag: resp.Header.Get("ETag"),
+		Next: next,
+		Body: body,
+	})
+
+	return next, nil
+}
+
+// checkRateLimit turns a response that was refused because the API rate
+// limit is used up into a RateLimitError.
+func checkRateLimit(resp *http.Response) error {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+
+	rateLimitError := &RateLimitError{Authenticated: GitHubToken != ""}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimitError.Reset = time.Unix(reset, 0)
+	}
+
+	return rateLimitError
+}
+
+// RateLimitError is returned when the GitHub API refuses a request because
+// the rate limit is used up.
+type RateLimitError struct {
+	Reset         time.Time
+	Authenticated bool
+}
+
+func (e *RateLimitError) Error() string {
+	message := "GitHub API rate limit exceeded"
+	if !e.Reset.IsZero() {
+		message += fmt.Sprintf(", it resets at %s (in %s)",
+			e.Reset.Local().Format("15:04:05"), time.Until(e.Reset).Round(time.Minute))
+	}
+
+	if !e.Authenticated {
+		message += ". Set github_token in the configuration file, or the GITHUB_TOKEN environment variable, to raise the limit"
+	}
+
+	return message
+}
+
+// nextPageURL returns the rel="next" URL from a GitHub Link header.
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+
+		for _, parameter := range sections[1:] {
+			if strings.TrimSpace(parameter) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(sections[0]), "<>")
+			}
+		}
+	}
+
+	return ""
+}
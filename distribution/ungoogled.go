@@ -0,0 +1,93 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"unchrome_launcher/archive"
+	"unchrome_launcher/constants"
+)
+
+// ungoogled provides the ungoogled-chromium-windows builds from the
+// ungoogled-software project, or its portable Linux builds on Linux.
+type ungoogled struct {
+	gitHub
+}
+
+func init() {
+	if runtime.GOOS == "linux" {
+		Register(ungoogled{gitHub{
+			repository:  constants.UNGOOGLED_CHROMIUM_LINUX_GITHUB_REPOSITORY,
+			assetSuffix: constants.UNGOOGLED_CHROMIUM_LINUX_ASSET_NAME,
+		}})
+		return
+	}
+
+	Register(ungoogled{gitHub{
+		repository:  constants.UNGOOGLED_CHROMIUM_WINDOWS_GITHUB_REPOSITORY,
+		assetSuffix: constants.UNGOOGLED_CHROMIUM_WINDOWS_ASSET_NAME,
+	}})
+}
+
+func (ungoogled) Name() string {
+	return constants.UNGOOGLED_CHROMIUM_DISTRIBUTION
+}
+
+func (ungoogled) Layout() Layout {
+	return Layout{StripComponents: archive.AutoStripComponents}
+}
+
+func (ungoogled) Executable() string {
+	return chromeExecutable()
+}
+
+// ParseVersion parses tags such as "139.0.7258.154-1.1", where the part
+// after the dash is the ungoogled-chromium revision.
+func (ungoogled) ParseVersion(tag string) (Version, error) {
+	version, rest, err := parseChromiumVersion(tag)
+	if err != nil {
+		return Version{}, err
+	}
+
+	if strings.HasPrefix(rest, "-") {
+		revision, err := parseDottedNumbers(strings.TrimPrefix(rest, "-"))
+		if err != nil {
+			return Version{}, fmt.Errorf("tag [%s] has an invalid revision: %w", tag, err)
+		}
+		version.Revision = revision
+	}
+
+	return version, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// chromiumVersionPattern matches the Chromium version at the start of a tag,
+// with or without a leading "v".
+var chromiumVersionPattern = regexp.MustCompile(`^[vV]?(\d+)\.(\d+)\.(\d+)\.(\d+)`)
+
+// Version is a parsed release tag. Chromium holds the four part Chromium
+// version, and Revision the distribution's own build numbers for that
+// Chromium version, if it has any. Build identifies the build for
+// distributions that name it without numbering it, such as a commit hash, so
+// it tells rebuilds apart but does not order them.
+type Version struct {
+	Chromium [4]int
+	Revision []int
+	Build    string
+}
+
+// IsRebuild reports if v and other are the same version built again, which
+// only distributions that set Build can tell.
+func (v Version) IsRebuild(other Version) bool {
+	return v.Compare(other) == 0 && v.Build != "" && other.Build != "" && v.Build != other.Build
+}
+
+// Compare returns -1 if v is older than other, 0 if they are the same and 1
+// if v is newer.
+func (v Version) Compare(other Version) int {
+	for i := range v.Chromium {
+		if v.Chromium[i] != other.Chromium[i] {
+			return compareInts(v.Chromium[i], other.Chromium[i])
+		}
+	}
+
+	for i := 0; i < len(v.Revision) || i < len(other.Revision); i++ {
+		a, b := 0, 0
+		if i < len(v.Revision) {
+			a = v.Revision[i]
+		}
+		if i < len(other.Revision) {
+			b = other.Revision[i]
+		}
+
+		if a != b {
+			return compareInts(a, b)
+		}
+	}
+
+	return 0
+}
+
+// String formats v as "chromium-revision".
+func (v Version) String() string {
+	var parts []string
+	for _, number := range v.Chromium {
+		parts = append(parts, strconv.Itoa(number))
+	}
+
+	version := strings.Join(parts, ".")
+	if len(v.Revision) > 0 {
+		var revision []string
+		for _, number := range v.Revision {
+			revision = append(revision, strconv.Itoa(number))
+		}
+		version += "-" + strings.Join(revision, ".")
+	}
+
+	return version
+}
+
+// parseChromiumVersion parses the Chromium version at the start of tag and
+// returns it together with the remainder of the tag.
+func parseChromiumVersion(tag string) (Version, string, error) {
+	match := chromiumVersionPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return Version{}, "", fmt.Errorf("tag [%s] does not start with a Chromium version", tag)
+	}
+
+	var version Version
+	for i := range version.Chromium {
+		number, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return Version{}, "", fmt.Errorf("tag [%s] has an invalid Chromium version: %w", tag, err)
+		}
+		version.Chromium[i] = number
+	}
+
+	return version, tag[len(match[0]):], nil
+}
+
+// parseDottedNumbers parses "1.2.3" into its numbers.
+func parseDottedNumbers(text string) ([]int, error) {
+	var numbers []int
+	for _, part := range strings.Split(text, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func compareInts(a int, b int) int {
+	if a < b {
+		return -1
+	}
+	return 1
+}
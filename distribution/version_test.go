@@ -0,0 +1,154 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		provider Provider
+		tag      string
+		chromium [4]int
+		revision []int
+		build    string
+		invalid  bool
+	}{
+		{provider: ungoogled{}, tag: "139.0.7258.154-1.1", chromium: [4]int{139, 0, 7258, 154}, revision: []int{1, 1}},
+		{provider: ungoogled{}, tag: "139.0.7258.154-2", chromium: [4]int{139, 0, 7258, 154}, revision: []int{2}},
+		{provider: ungoogled{}, tag: "139.0.7258.154", chromium: [4]int{139, 0, 7258, 154}},
+		{provider: ungoogled{}, tag: "139.0.7258.154-x", invalid: true},
+		{provider: ungoogled{}, tag: "latest", invalid: true},
+		{provider: cromite{}, tag: "v139.0.7258.158-7ef8fd4", chromium: [4]int{139, 0, 7258, 158}, build: "7ef8fd4"},
+		{provider: cromite{}, tag: "139.0.7258.158", chromium: [4]int{139, 0, 7258, 158}},
+		{provider: cromite{}, tag: "v139.0.7258", invalid: true},
+		{provider: winchrome{}, tag: "v139.0.7258.155-M139.0.7258.155-r1477651-Win64", chromium: [4]int{139, 0, 7258, 155}, revision: []int{1477651}},
+		{provider: winchrome{}, tag: "v139.0.7258.155", chromium: [4]int{139, 0, 7258, 155}},
+	}
+
+	for _, test := range tests {
+		version, err := test.provider.ParseVersion(test.tag)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("%s.ParseVersion(%q) = %v, want an error", test.provider.Name(), test.tag, version)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s.ParseVersion(%q) error = %v", test.provider.Name(), test.tag, err)
+			continue
+		}
+
+		if version.Chromium != test.chromium || !slices.Equal(version.Revision, test.revision) || version.Build != test.build {
+			t.Errorf("%s.ParseVersion(%q) = %+v, want %v-%v %q", test.provider.Name(), test.tag, version, test.chromium, test.revision, test.build)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "139.0.7258.154-1.1", b: "139.0.7258.154-1.1", want: 0},
+		{a: "139.0.7258.154-1.1", b: "139.0.7258.155-1.1", want: -1},
+		{a: "140.0.7339.80-1.1", b: "139.0.7258.154-1.1", want: 1},
+		{a: "139.0.7258.154-1.2", b: "139.0.7258.154-1.1", want: 1},
+		{a: "139.0.7258.154-2", b: "139.0.7258.154-1.9", want: 1},
+		{a: "139.0.7258.154-1", b: "139.0.7258.154-1.0", want: 0},
+		{a: "139.0.7258.154", b: "139.0.7258.154-1", want: -1},
+		{a: "139.0.7258.99-1", b: "139.0.7258.100-1", want: -1},
+	}
+
+	for _, test := range tests {
+		a, err := ungoogled{}.ParseVersion(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ungoogled{}.ParseVersion(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := a.Compare(b); got != test.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestVersionIsRebuild(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{a: "v139.0.7258.158-7ef8fd4", b: "v139.0.7258.158-1a2b3c4", want: true},
+		{a: "v139.0.7258.158-7ef8fd4", b: "v139.0.7258.158-7ef8fd4", want: false},
+		{a: "v140.0.7339.81-7ef8fd4", b: "v139.0.7258.158-1a2b3c4", want: false},
+		{a: "v139.0.7258.158-7ef8fd4", b: "v139.0.7258.158", want: false},
+	}
+
+	for _, test := range tests {
+		a, err := cromite{}.ParseVersion(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := cromite{}.ParseVersion(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := a.IsRebuild(b); got != test.want {
+			t.Errorf("IsRebuild(%s, %s) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	tests := []struct {
+		version Version
+		want    string
+	}{
+		{version: Version{Chromium: [4]int{139, 0, 7258, 154}, Revision: []int{1, 1}}, want: "139.0.7258.154-1.1"},
+		{version: Version{Chromium: [4]int{139, 0, 7258, 158}}, want: "139.0.7258.158"},
+	}
+
+	for _, test := range tests {
+		if got := test.version.String(); got != test.want {
+			t.Errorf("String() = %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package distribution
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"unchrome_launcher/archive"
+	"unchrome_launcher/constants"
+)
+
+// winchrome provides the ungoogled-chromium builds published by the
+// macchrome/winchrome project.
+type winchrome struct {
+	gitHub
+}
+
+func init() {
+	Register(winchrome{gitHub{
+		repository:  constants.UNGOOGLED_WINCHROME_GITHUB_REPOSITORY,
+		assetSuffix: constants.UNGOOGLED_WINCHROME_ASSET_NAME,
+	}})
+}
+
+func (winchrome) Name() string {
+	return constants.UNGOOGLED_WINCHROME_DISTRIBUTION
+}
+
+func (winchrome) Layout() Layout {
+	return Layout{StripComponents: archive.AutoStripComponents}
+}
+
+func (winchrome) Executable() string {
+	return constants.CHROME_APPLICATION_NAME
+}
+
+// winchromeRevisionPattern matches the Chromium branch revision in a
+// winchrome tag.
+var winchromeRevisionPattern = regexp.MustCompile(`-r(\d+)`)
+
+// ParseVersion parses tags such as
+// "v139.0.7258.155-M139.0.7258.155-r1477651-Win64", using the "r" revision
+// as the build number.
+func (winchrome) ParseVersion(tag string) (Version, error) {
+	version, rest, err := parseChromiumVersion(tag)
+	if err != nil {
+		return Version{}, err
+	}
+
+	if match := winchromeRevisionPattern.FindStringSubmatch(rest); match != nil {
+		revision, err := strconv.Atoi(match[1])
+		if err != nil {
+			return Version{}, fmt.Errorf("tag [%s] has an invalid revision: %w", tag, err)
+		}
+		version.Revision = []int{revision}
+	}
+
+	return version, nil
+}
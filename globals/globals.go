@@ -1,3 +1,3 @@
-package globals
-
+package globals
+
 var ExeDir string
\ No newline at end of file
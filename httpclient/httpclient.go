@@ -0,0 +1,260 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ConnectTimeout limits how long connecting to a server, including the TLS
+// handshake, may take.
+var ConnectTimeout = 30 * time.Second
+
+// ReadTimeout limits how long a request may wait for the response headers,
+// and how long a response body may go without receiving any data. Large
+// downloads are not limited as a whole, only while they are stalled.
+var ReadTimeout = 60 * time.Second
+
+// Retries is how many times a failed request is retried.
+var Retries = 3
+
+// retryBaseWait is the wait before the first retry, which doubles with every
+// following one.
+var retryBaseWait = time.Second
+
+// retryMaxWait is the longest wait before a retry. A server asking for a
+// longer wait with Retry-After gets its response returned instead.
+const retryMaxWait = 30 * time.Second
+
+// ErrStalled is the cause of a response body read that received no data for
+// ReadTimeout.
+var ErrStalled = errors.New("no data received")
+
+var client = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: ConnectTimeout, KeepAlive: 30 * time.Second}
+			return dialer.DialContext(ctx, network, address)
+		},
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	},
+}
+
+// Get requests url like Do.
+func Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return Do(req)
+}
+
+// Do sends req, which must not have a body, and retries it up to Retries
+// times when the connection fails or the server answers with a 5xx or 429
+// status. Retries wait with an exponential, jittered backoff, or as long as
+// a Retry-After header asks for. The last response or error is returned
+// when every attempt failed.
+func Do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := send(req)
+
+		wait, retry := retryAfter(resp, err, attempt)
+		if !retry || attempt >= Retries {
+			return resp, err
+		}
+
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+
+		log.Printf("Request for [%s] failed: %s, retrying in %s (%d of %d).", req.URL, reason, wait.Round(time.Second), attempt+1, Retries)
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// send sends a single attempt of req, limited by ConnectTimeout and
+// ReadTimeout.
+func send(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancelCause(req.Context())
+
+	// The timer covers the wait for the response headers first, and is then
+	// reset by every read of the body.
+	timer := time.AfterFunc(ConnectTimeout+ReadTimeout, func() {
+		cancel(ErrStalled)
+	})
+
+	resp, err := client.Do(req.Clone(ctx))
+	if err != nil {
+		timer.Stop()
+		cancel(nil)
+		return nil, withCause(ctx, err)
+	}
+
+	resp.Body = &stallTimeoutBody{
+		body:   resp.Body,
+		ctx:    ctx,
+		timer:  timer,
+		cancel: cancel,
+	}
+
+	return resp, nil
+}
+
+// retryAfter reports if a request that ended with resp or err should be
+// retried, and how long to wait before attempt+1.
+func retryAfter(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		return Backoff(attempt), IsRetryable(err)
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+		return 0, false
+	}
+
+	// Waiting for a used up GitHub API rate limit takes far too long.
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return 0, false
+	}
+
+	if wait, found := parseRetryAfter(resp.Header.Get("Retry-After")); found {
+		return wait, wait <= retryMaxWait
+	}
+
+	return Backoff(attempt), true
+}
+
+// Backoff returns the wait before attempt+1, doubling with every attempt
+// and jittered by up to half of it either way, so that many launchers do not
+// retry in step.
+func Backoff(attempt int) time.Duration {
+	wait := retryBaseWait << attempt
+	if wait <= 0 || wait > retryMaxWait {
+		wait = retryMaxWait
+	}
+
+	return wait/2 + rand.N(wait)
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// IsRetryable reports if err is a failure of the connection that may well
+// succeed when tried again, such as a reset connection or a timeout.
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrStalled) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	if isConnectionError(err) {
+		return true
+	}
+
+	var netError net.Error
+	return errors.As(err, &netError) && netError.Timeout()
+}
+
+// withCause returns err, unless ctx was cancelled because the request
+// stalled, in which case ErrStalled is returned rather than a bare
+// "context canceled".
+func withCause(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), ErrStalled) {
+		return fmt.Errorf("%w for %s", ErrStalled, ReadTimeout)
+	}
+
+	return err
+}
+
+// stallTimeoutBody cancels its request when a read receives no data for
+// ReadTimeout.
+type stallTimeoutBody struct {
+	body   io.ReadCloser
+	ctx    context.Context
+	timer  *time.Timer
+	cancel context.CancelCauseFunc
+}
+
+func (b *stallTimeoutBody) Read(p []byte) (int, error) {
+	b.timer.Reset(ReadTimeout)
+
+	n, err := b.body.Read(p)
+	if err != nil && err != io.EOF {
+		err = withCause(b.ctx, err)
+	}
+
+	return n, err
+}
+
+func (b *stallTimeoutBody) Close() error {
+	b.timer.Stop()
+	err := b.body.Close()
+	b.cancel(nil)
+	return err
+}
@@ -0,0 +1,279 @@
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fastRetries shortens the waits between retries for the duration of a test.
+func fastRetries(t *testing.T, retries int) {
+	baseWait, savedRetries := retryBaseWait, Retries
+	retryBaseWait, Retries = time.Millisecond, retries
+	t.Cleanup(func() { retryBaseWait, Retries = baseWait, savedRetries })
+}
+
+// failingServer answers the first failures requests with respond, and every
+// later one with 200 OK. It counts the requests it received.
+func failingServer(t *testing.T, failures int32, respond func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			respond(w)
+			return
+		}
+
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestGetRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		respond  func(w http.ResponseWriter)
+		status   int
+		requests int32
+	}{
+		{
+			name:     "server error",
+			respond:  func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			status:   http.StatusOK,
+			requests: 3,
+		},
+		{
+			name: "too many requests with Retry-After",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			status:   http.StatusOK,
+			requests: 3,
+		},
+		{
+			name: "Retry-After longer than the longest wait",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			status:   http.StatusTooManyRequests,
+			requests: 1,
+		},
+		{
+			name: "used up rate limit",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.WriteHeader(http.StatusForbidden)
+			},
+			status:   http.StatusForbidden,
+			requests: 1,
+		},
+		{
+			name:     "client error",
+			respond:  func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			status:   http.StatusNotFound,
+			requests: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fastRetries(t, 3)
+			server, requests := failingServer(t, 2, test.respond)
+
+			resp, err := Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.status {
+				t.Errorf("Get() status = %d, want %d", resp.StatusCode, test.status)
+			}
+			if got := requests.Load(); got != test.requests {
+				t.Errorf("server received %d requests, want %d", got, test.requests)
+			}
+		})
+	}
+}
+
+func TestGetGivesUpAfterRetries(t *testing.T) {
+	fastRetries(t, 2)
+	server, requests := failingServer(t, 10, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	resp, err := Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Get() status = %d, want %d", resp.StatusCode, http.StatusBadGateway)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+func TestGetRetriesResetConnection(t *testing.T) {
+	fastRetries(t, 3)
+	server, requests := failingServer(t, 2, func(w http.ResponseWriter) {
+		// Drop the connection with a reset rather than an orderly close.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			panic(err)
+		}
+		conn.(*net.TCPConn).SetLinger(0)
+		conn.Close()
+	})
+
+	resp, err := Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "ok" {
+		t.Errorf("Get() body = %q, %v, want %q", body, err, "ok")
+	}
+	if got := requests.Load(); got < 3 {
+		t.Errorf("server received %d requests, want at least 3", got)
+	}
+}
+
+func TestStalledBody(t *testing.T) {
+	readTimeout := ReadTimeout
+	ReadTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { ReadTimeout = readTimeout })
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	resp, err := Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrStalled) {
+		t.Fatalf("reading the body error = %v, want %v", err, ErrStalled)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("IsRetryable(%v) = false, want true", err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "stalled", err: fmt.Errorf("%w for 1m0s", ErrStalled), want: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "connection reset", err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, want: true},
+		{name: "timeout", err: &net.DNSError{Err: "timeout", IsTimeout: true}, want: true},
+		{name: "unknown host", err: &net.DNSError{Err: "no such host", IsNotFound: true}, want: false},
+		{name: "other", err: errors.New("unsupported protocol scheme"), want: false},
+	}
+
+	for _, test := range tests {
+		if got := IsRetryable(test.err); got != test.want {
+			t.Errorf("%s: IsRetryable(%v) = %v, want %v", test.name, test.err, got, test.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		found bool
+	}{
+		{value: "", found: false},
+		{value: "120", want: 2 * time.Minute, found: true},
+		{value: "-5", want: 0, found: true},
+		{value: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, found: true},
+		{value: "soon", found: false},
+	}
+
+	for _, test := range tests {
+		got, found := parseRetryAfter(test.value)
+		if got != test.want || found != test.found {
+			t.Errorf("parseRetryAfter(%q) = %s, %v, want %s, %v", test.value, got, found, test.want, test.found)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		wait := retryBaseWait << attempt
+		if wait <= 0 || wait > retryMaxWait {
+			wait = retryMaxWait
+		}
+
+		got := Backoff(attempt)
+		if got < wait/2 || got >= wait/2+wait {
+			t.Errorf("Backoff(%d) = %s, want between %s and %s", attempt, got, wait/2, wait/2+wait)
+		}
+	}
+}
@@ -1,60 +1,60 @@
-/*
-Copyright © 2018-2025 Jeff Lanzarotta
-All rights reserved.
-
-Redistribution and use in source and binary forms, with or without
-modification, are permitted provided that the following conditions are met:
-
- 1. Redistributions of source code must retain the above copyright notice,
-    this list of conditions and the following disclaimer.
-
- 2. Redistributions in binary form must reproduce the above copyright notice,
-    this list of conditions and the following disclaimer in the documentation
-    and/or other materials provided with the distribution.
-
- 3. Neither the name of the copyright holder nor the names of its contributors
-    may be used to endorse or promote products derived from this software
-    without specific prior written permission.
-
-THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
-AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
-IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
-ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
-LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
-CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
-SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
-INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
-CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
-ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
-POSSIBILITY OF SUCH DAMAGE.
-*/
-package main
-
-import (
-	"fmt"
-	"os"
-	"path/filepath"
-	"unchrome_launcher/cmd"
-	"unchrome_launcher/constants"
-	"unchrome_launcher/globals"
-	"unchrome_launcher/logger"
-
-	"github.com/fatih/color"
-)
-
-
-func main() {
-	// Find the directory where the Unchrome Launcher executable is located.
-    exePath, err := os.Executable()
-    if err != nil {
-		fmt.Printf("%s: [%v]\n",
-			color.RedString(constants.FATAL_NORMAL_CASE), err)
-		os.Exit(1)
-    }
-    globals.ExeDir = filepath.Dir(exePath)
-
-	logger.InitLogFile(filepath.Join(globals.ExeDir, constants.APPLICATION_NAME_LOWERCASE + ".log"))
-
-  	// If the user does not submit a command, use the default.
-  	cmd.Execute(constants.DEFAULT_COMMAND)
+/*
+Copyright © 2018-2025 Jeff Lanzarotta
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unchrome_launcher/cmd"
+	"unchrome_launcher/constants"
+	"unchrome_launcher/globals"
+	"unchrome_launcher/logger"
+
+	"github.com/fatih/color"
+)
+
+
+func main() {
+	// Find the directory where the Unchrome Launcher executable is located.
+    exePath, err := os.Executable()
+    if err != nil {
+		fmt.Printf("%s: [%v]\n",
+			color.RedString(constants.FATAL_NORMAL_CASE), err)
+		os.Exit(1)
+    }
+    globals.ExeDir = filepath.Dir(exePath)
+
+	logger.InitLogFile(filepath.Join(globals.ExeDir, constants.APPLICATION_NAME_LOWERCASE + ".log"))
+
+  	// If the user does not submit a command, use the default.
+  	cmd.Execute(constants.DEFAULT_COMMAND)
 }
\ No newline at end of file